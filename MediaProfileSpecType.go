@@ -1,3 +0,0 @@
-// Code generated by kurento-go-generator. DO NOT EDIT.
-
-package kurento
@@ -1,6 +1,7 @@
 package kurento
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -47,6 +48,7 @@ type IMediaObject interface {
 	addChild(IMediaObject)
 
 	setConnection(*Connection)
+	getConnection() *Connection
 }
 
 // Create object "m" with given "options"
@@ -60,8 +62,8 @@ func (elem *MediaObject) Create(m IMediaObject, options map[string]interface{})
 		"constructorParams": constparams,
 	}
 
-	if elem.connection.SessionId != "" {
-		reqparams["sessionId"] = elem.connection.SessionId
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
 	}
 	req["params"] = reqparams
 
@@ -69,7 +71,7 @@ func (elem *MediaObject) Create(m IMediaObject, options map[string]interface{})
 		log.Printf("CREATE sending request: %+v\n", req)
 	}
 
-	m.setConnection(elem.connection)
+	m.setConnection(elem.connection.base())
 
 	res := <-elem.connection.Request(req)
 
@@ -99,8 +101,8 @@ func (elem *MediaObject) Release() error {
 	reqparams := map[string]interface{}{
 		"object": elem.String(),
 	}
-	if elem.connection.SessionId != "" {
-		reqparams["sessionId"] = elem.connection.SessionId
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
 	}
 	req["params"] = reqparams
 
@@ -127,15 +129,18 @@ func (elem *MediaObject) Subscribe(event string, cb eventHandler) (string, error
 		"type":   event,
 		"object": elem.String(),
 	}
-	if elem.connection.SessionId != "" {
-		reqparams["sessionId"] = elem.connection.SessionId
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
 	}
 	req["params"] = reqparams
 	if logLevel > 0 {
 		log.Printf("SUBSCRIBE sending request: %+v\n", req)
 	}
 	res := <-elem.connection.Request(req)
-	handlerId := res.Result["value"].(string)
+	if res.Error != nil {
+		return "", fmt.Errorf("[%d] %s %s", res.Error.Code, res.Error.Message, res.Error.Data)
+	}
+	handlerId, _ := res.Result["value"].(string)
 	if logLevel > 0 {
 		log.Println("SUBSCRIBE response handlerId ", handlerId)
 	}
@@ -144,16 +149,36 @@ func (elem *MediaObject) Subscribe(event string, cb eventHandler) (string, error
 	elem.connection.Subscribe(event, elem.String(), handlerId, cb)
 
 	// pass back the token so can be unregistered
+	return handlerId, nil
+}
+
+// Unsubscribe removes the handler registered by Subscribe for the event.
+func (elem *MediaObject) Unsubscribe(event, handlerId string) error {
+	// the handler is removed first, so no event is delivered after this call
+	elem.connection.Unsubscribe(event, elem.String(), handlerId)
+
+	req := elem.getUnsubscribeRequest()
+	reqparams := map[string]interface{}{
+		"subscription": handlerId,
+		"object":       elem.String(),
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+	if logLevel > 0 {
+		log.Printf("UNSUBSCRIBE sending request: %+v\n", req)
+	}
+	res := <-elem.connection.Request(req)
 	if res.Error != nil {
-		return handlerId, fmt.Errorf("[%d] %s %s", res.Error.Code, res.Error.Message, res.Error.Data)
+		return fmt.Errorf("[%d] %s %s", res.Error.Code, res.Error.Message, res.Error.Data)
 	}
-
-	return handlerId, nil
+	return nil
 }
 
 // Create an object in memory that represents a remote object without creating it
 func HydrateMediaObject(id string, parent IMediaObject, c *Connection, elem IMediaObject) error {
-	elem.setConnection(c)
+	elem.setConnection(c.base())
 	elem.setId(id)
 	if parent != nil {
 		parent.addChild(elem)
@@ -161,11 +186,121 @@ func HydrateMediaObject(id string, parent IMediaObject, c *Connection, elem IMed
 	return nil
 }
 
+// TypeFromId returns the Kurento type of an object from its ID, e.g.
+// "WebRtcEndpoint" for "<pipeline>_kurento.MediaPipeline/<uuid>_kurento.WebRtcEndpoint"
+// or "ServerManager" for "manager_ServerManager".
+func TypeFromId(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	if i := strings.LastIndex(id, "."); i >= 0 {
+		return id[i+1:]
+	}
+	if i := strings.LastIndex(id, "_"); i >= 0 {
+		return id[i+1:]
+	}
+	return id
+}
+
+// hydrateMediaObjects builds the objects referenced by a response value,
+// which can be a single ID or a list of IDs. Unknown types are skipped.
+func hydrateMediaObjects(c *Connection, value interface{}) []IMediaObject {
+	var ids []string
+	switch v := value.(type) {
+	case string:
+		ids = append(ids, v)
+	case []interface{}:
+		for _, id := range v {
+			if s, ok := id.(string); ok {
+				ids = append(ids, s)
+			}
+		}
+	}
+
+	ret := make([]IMediaObject, 0, len(ids))
+	for _, id := range ids {
+		elem := NewMediaObject(TypeFromId(id))
+		if elem == nil {
+			if logLevel > 0 {
+				log.Printf("unknown type for object %s\n", id)
+			}
+			continue
+		}
+		HydrateMediaObject(id, nil, c, elem)
+		ret = append(ret, elem)
+	}
+	return ret
+}
+
+// decodeValue fills dst, which must be a pointer, with a value decoded from a
+// response or an event. Remote objects found in dst are bound to connection c.
+func decodeValue(c *Connection, value interface{}, dst interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(raw, dst); err != nil {
+		return err
+	}
+	bindConnection(reflect.ValueOf(dst), c)
+	return nil
+}
+
+// bindConnection sets the connection of every MediaObject reachable from v.
+func bindConnection(v reflect.Value, c *Connection) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if !v.IsNil() {
+			bindConnection(v.Elem(), c)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			bindConnection(v.Index(i), c)
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(MediaObject{}) {
+			if v.CanAddr() {
+				v.Addr().Interface().(*MediaObject).setConnection(c.base())
+			}
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				bindConnection(v.Field(i), c)
+			}
+		}
+	}
+}
+
+// UnmarshalJSON reads an object reference, as sent by KMS in complex types
+// and events, which is the ID of the object.
+func (m *MediaObject) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &m.Id)
+}
+
 // Implement setConnection that allows element to handle connection
 func (elem *MediaObject) setConnection(c *Connection) {
 	elem.connection = c
 }
 
+func (elem *MediaObject) getConnection() *Connection {
+	return elem.connection
+}
+
+// pipelineOf returns the pipeline m belongs to, built from the ID of m.
+func pipelineOf(m IMediaObject) *MediaPipeline {
+	if p, ok := m.(*MediaPipeline); ok {
+		return p
+	}
+	id := m.String()
+	if i := strings.Index(id, "/"); i >= 0 {
+		id = id[:i]
+	}
+	p := &MediaPipeline{}
+	HydrateMediaObject(id, nil, m.getConnection(), p)
+	return p
+}
+
 // Set parent of current element
 // BUG(recursion) a recursion happens while testing, I must find why
 func (elem *MediaObject) setParent(m IMediaObject) {
@@ -215,6 +350,13 @@ func (m *MediaObject) getSubscribeRequest() map[string]interface{} {
 	return req
 }
 
+func (m *MediaObject) getUnsubscribeRequest() map[string]interface{} {
+	req := m.getCreateRequest()
+	req["method"] = "unsubscribe"
+
+	return req
+}
+
 // String implements fmt.Stringer interface, return ID
 func (m *MediaObject) String() string {
 	return m.Id
@@ -244,7 +386,7 @@ func setIfNotEmpty(param map[string]interface{}, name string, t interface{}) {
 		if v != "" {
 			param[name] = v
 		}
-	case int, float64:
+	case int, int64, float64:
 		if v != 0 {
 			param[name] = v
 		}
@@ -261,21 +403,6 @@ func setIfNotEmpty(param map[string]interface{}, name string, t interface{}) {
 		}
 	case ICustomSerializer:
 		param[name] = v.CustomSerialize()
-		/*default:
-		innerParam := make(map[string]interface{})
-		val := reflect.ValueOf(v)
-		log.Printf("type %v", val)
-		// TODO: look this stuff up somehow? Expose as interface as they do above? Tricky
-		innerParam["__module__"] = "kurento"
-		innerParam["__type__"] = "IceCandidate"
-
-		for i := 0; i < val.NumField(); i++ {
-			fieldName := lowerFirst(val.Type().Field(i).Name)
-			//log.Printf("setIfNotEmpty %s %v %v", fieldName, val.Field(i).Interface(), val.Field(i).Type().Name())
-			setIfNotEmpty(innerParam, fieldName, val.Field(i).Interface())
-		}
-		param[name] = innerParam
-		log.Printf("Setting %s to %v", name, innerParam)*/
 	}
 }
 
@@ -0,0 +1,25 @@
+package kurento_test
+
+import (
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestTypeFromId(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"3f0b_kurento.MediaPipeline", "MediaPipeline"},
+		{"3f0b_kurento.MediaPipeline/9c1d_kurento.WebRtcEndpoint", "WebRtcEndpoint"},
+		{"3f0b_kurento.MediaPipeline/9c1d_kurento.HubPort", "HubPort"},
+		{"manager_ServerManager", "ServerManager"},
+		{"pipe/src", "src"},
+	}
+	for _, tt := range tests {
+		if got := kurento.TypeFromId(tt.id); got != tt.want {
+			t.Errorf("TypeFromId(%q) = %q, want %q", tt.id, got, tt.want)
+		}
+	}
+}
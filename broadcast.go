@@ -0,0 +1,389 @@
+package kurento
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BroadcastOptions configure a Broadcast.
+type BroadcastOptions struct {
+	// MaxViewers is the maximum number of viewers, 0 for no limit.
+	MaxViewers int
+
+	// UseDispatcher fans the presenter out through a DispatcherOneToMany
+	// instead of connecting it to every viewer.
+	UseDispatcher bool
+
+	// OnIceCandidate sends a local candidate of the endpoint of peer id to
+	// that peer. Required.
+	OnIceCandidate func(id string, candidate IceCandidate) error
+
+	// OnIceGatheringDone tells peer id that all its candidates were sent.
+	// Optional.
+	OnIceGatheringDone func(id string) error
+
+	// OnViewerReleased is called for every viewer released because the
+	// presenter left. Optional.
+	OnViewerReleased func(id string)
+}
+
+var (
+	ErrNoPresenter  = errors.New("kurento: no presenter in the room")
+	ErrRoomFull     = errors.New("kurento: maximum number of peers reached")
+	ErrPeerExists   = errors.New("kurento: peer already in the room")
+	ErrPeerNotFound = errors.New("kurento: peer not found")
+	ErrRoomReleased = errors.New("kurento: room has been released")
+
+	errMissingOnIce = errors.New("kurento: OnIceCandidate is required")
+)
+
+// broadcastPeer is the presenter or a viewer of a Broadcast.
+type broadcastPeer struct {
+	id       string
+	endpoint *WebRtcEndpoint
+	port     *HubPort
+	trickle  *TrickleSession
+}
+
+func (p *broadcastPeer) release() error {
+	var ret error
+	if p.trickle != nil {
+		p.trickle.Close()
+	}
+	if p.port != nil {
+		ret = p.port.Release()
+	}
+	if err := p.endpoint.Release(); err != nil {
+		ret = err
+	}
+	return ret
+}
+
+// Broadcast sends the media of one presenter to many viewers, each of them
+// being a WebRTC peer. Viewers are released when the presenter leaves.
+type Broadcast struct {
+	Pipeline   *MediaPipeline
+	Dispatcher *DispatcherOneToMany
+
+	options   BroadcastOptions
+	lock      sync.Mutex
+	presenter *broadcastPeer
+	viewers   map[string]*broadcastPeer
+	// viewers whose endpoint is being created
+	joining  map[string]bool
+	released bool
+}
+
+// NewBroadcast creates the pipeline of a broadcast on c.
+func NewBroadcast(c *Connection, options BroadcastOptions) (*Broadcast, error) {
+	if options.OnIceCandidate == nil {
+		return nil, errMissingOnIce
+	}
+	b := &Broadcast{
+		Pipeline: &MediaPipeline{},
+		options:  options,
+		viewers:  make(map[string]*broadcastPeer),
+		joining:  make(map[string]bool),
+	}
+	if err := c.Create(b.Pipeline, nil); err != nil {
+		return nil, err
+	}
+	if options.UseDispatcher {
+		b.Dispatcher = &DispatcherOneToMany{}
+		if err := b.Pipeline.Create(b.Dispatcher, nil); err != nil {
+			b.Pipeline.Release()
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// newTricklePeer creates a WebRTC endpoint in pipeline and the trickle
+// session of peer id, whose candidates are given to onCandidate and onDone.
+// onDone may be nil.
+func newTricklePeer(pipeline *MediaPipeline, id string, options WebRtcEndpointOptions,
+	onCandidate func(id string, candidate IceCandidate) error, onDone func(id string) error) (*WebRtcEndpoint, *TrickleSession, error) {
+
+	endpoint := &WebRtcEndpoint{}
+	if err := pipeline.Create(endpoint, options.ConstructorParams()); err != nil {
+		return nil, nil, err
+	}
+	trickle, err := NewTrickleSession(endpoint, TrickleCallbacks{
+		SendCandidate: func(candidate IceCandidate) error {
+			return onCandidate(id, candidate)
+		},
+		SendEndOfCandidates: func() error {
+			if onDone == nil {
+				return nil
+			}
+			return onDone(id)
+		},
+	})
+	if err != nil {
+		endpoint.Release()
+		return nil, nil, err
+	}
+	return endpoint, trickle, nil
+}
+
+// newPeer creates the endpoint of a peer, and its port when a dispatcher is
+// used.
+func (b *Broadcast) newPeer(id string, options WebRtcEndpointOptions) (*broadcastPeer, error) {
+	endpoint, trickle, err := newTricklePeer(b.Pipeline, id, options, b.options.OnIceCandidate, b.options.OnIceGatheringDone)
+	if err != nil {
+		return nil, err
+	}
+	p := &broadcastPeer{id: id, endpoint: endpoint, trickle: trickle}
+
+	if b.Dispatcher != nil {
+		p.port = &HubPort{}
+		if err := b.Dispatcher.Create(p.port, nil); err != nil {
+			p.port = nil
+			p.release()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// SetPresenter negotiates the presenter peer from its offer and gives the
+// answer to sendAnswer. An existing presenter is replaced: viewers are moved
+// to the new one before the old one is released.
+func (b *Broadcast) SetPresenter(id, offer string, sendAnswer func(answer string) error) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.released {
+		return ErrRoomReleased
+	}
+	if _, ok := b.viewers[id]; ok || b.joining[id] {
+		return ErrPeerExists
+	}
+
+	p, err := b.newPeer(id, WebRtcEndpointOptions{})
+	if err != nil {
+		return err
+	}
+
+	// the new presenter is negotiated before viewers are moved to it, so a
+	// failure leaves the current presenter in place
+	if err := p.trickle.AcceptOffer(offer, sendAnswer); err != nil {
+		p.release()
+		return err
+	}
+	if err := b.connectPresenter(p); err != nil {
+		p.release()
+		if b.presenter != nil {
+			b.connectPresenter(b.presenter)
+		}
+		return err
+	}
+
+	old := b.presenter
+	b.presenter = p
+	if old != nil {
+		return old.release()
+	}
+	return nil
+}
+
+// connectPresenter makes p the source of every viewer.
+func (b *Broadcast) connectPresenter(p *broadcastPeer) error {
+	if b.Dispatcher != nil {
+		if err := p.endpoint.Connect(p.port, "", "", ""); err != nil {
+			return err
+		}
+		return b.Dispatcher.SetSource(p.port)
+	}
+	for _, v := range b.viewers {
+		if err := p.endpoint.Connect(v.endpoint, "", "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemovePresenter releases the presenter and every viewer.
+func (b *Broadcast) RemovePresenter() error {
+	b.lock.Lock()
+	if b.presenter == nil {
+		b.lock.Unlock()
+		return ErrNoPresenter
+	}
+	released, err := b.removePresenter()
+	b.lock.Unlock()
+
+	// run once the lock is released, as the callback may call the broadcast
+	if b.options.OnViewerReleased != nil {
+		for _, id := range released {
+			b.options.OnViewerReleased(id)
+		}
+	}
+	return err
+}
+
+// removePresenter returns the IDs of the released viewers. It must be called
+// with the lock held.
+func (b *Broadcast) removePresenter() ([]string, error) {
+	var ret error
+	released := make([]string, 0, len(b.viewers))
+	for id, v := range b.viewers {
+		if err := v.release(); err != nil && ret == nil {
+			ret = err
+		}
+		delete(b.viewers, id)
+		released = append(released, id)
+	}
+	if b.Dispatcher != nil {
+		if err := b.Dispatcher.RemoveSource(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	if err := b.presenter.release(); err != nil && ret == nil {
+		ret = err
+	}
+	b.presenter = nil
+	return released, ret
+}
+
+// AddViewer negotiates a viewer peer from its offer, gives the answer to
+// sendAnswer and connects the presenter to it. The lock is not held while
+// KMS is called: the viewer is registered before the offer is accepted, so
+// its candidates are buffered until then.
+func (b *Broadcast) AddViewer(id, offer string, sendAnswer func(answer string) error) error {
+	b.lock.Lock()
+	switch {
+	case b.released:
+		b.lock.Unlock()
+		return ErrRoomReleased
+	case b.presenter == nil:
+		b.lock.Unlock()
+		return ErrNoPresenter
+	case b.presenter.id == id, b.viewers[id] != nil, b.joining[id]:
+		b.lock.Unlock()
+		return ErrPeerExists
+	case b.options.MaxViewers > 0 && len(b.viewers)+len(b.joining) >= b.options.MaxViewers:
+		b.lock.Unlock()
+		return ErrRoomFull
+	}
+	b.joining[id] = true
+	b.lock.Unlock()
+
+	v, err := b.newPeer(id, WebRtcEndpointOptions{})
+
+	b.lock.Lock()
+	delete(b.joining, id)
+	if err == nil {
+		switch {
+		case b.released:
+			err = ErrRoomReleased
+		case b.presenter == nil:
+			err = ErrNoPresenter
+		}
+		if err != nil {
+			v.release()
+		}
+	}
+	if err != nil {
+		b.lock.Unlock()
+		return err
+	}
+	b.viewers[id] = v
+	presenter := b.presenter
+	b.lock.Unlock()
+
+	if v.port != nil {
+		err = v.port.Connect(v.endpoint, "", "", "")
+	} else {
+		err = presenter.endpoint.Connect(v.endpoint, "", "", "")
+	}
+	if err == nil {
+		err = v.trickle.AcceptOffer(offer, sendAnswer)
+	}
+	if err != nil {
+		// unless the presenter left and released it meanwhile
+		b.lock.Lock()
+		registered := b.viewers[id] == v
+		if registered {
+			delete(b.viewers, id)
+		}
+		b.lock.Unlock()
+		if registered {
+			v.release()
+		}
+		return err
+	}
+	return nil
+}
+
+// RemoveViewer releases a viewer.
+func (b *Broadcast) RemoveViewer(id string) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	v, ok := b.viewers[id]
+	if !ok {
+		return ErrPeerNotFound
+	}
+	delete(b.viewers, id)
+	return v.release()
+}
+
+// AddIceCandidate gives a candidate received from peer id to its endpoint.
+func (b *Broadcast) AddIceCandidate(id string, candidate IceCandidate) error {
+	b.lock.Lock()
+	p := b.viewers[id]
+	if p == nil && b.presenter != nil && b.presenter.id == id {
+		p = b.presenter
+	}
+	b.lock.Unlock()
+
+	if p == nil {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	return p.trickle.AddRemoteCandidate(candidate)
+}
+
+// Presenter returns the ID of the presenter, and false if there is none.
+func (b *Broadcast) Presenter() (string, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.presenter == nil {
+		return "", false
+	}
+	return b.presenter.id, true
+}
+
+// Viewers returns the IDs of the viewers.
+func (b *Broadcast) Viewers() []string {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	ret := make([]string, 0, len(b.viewers))
+	for id := range b.viewers {
+		ret = append(ret, id)
+	}
+	return ret
+}
+
+// Release releases the pipeline, and every peer with it.
+func (b *Broadcast) Release() error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.released {
+		return nil
+	}
+	b.released = true
+	for _, p := range b.viewers {
+		p.trickle.Close()
+	}
+	if b.presenter != nil {
+		b.presenter.trickle.Close()
+	}
+	b.viewers = nil
+	b.presenter = nil
+	return b.Pipeline.Release()
+}
@@ -0,0 +1,157 @@
+package kurento_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// broadcastPeer expects the creation of the endpoint of a peer of the
+// broadcast "room", with its port if dispatched, up to its negotiation. The
+// endpoint is connected to its source, if any, before being negotiated.
+func (s *script) broadcastPeer(id string, dispatched bool, source string) {
+	s.call("create", map[string]interface{}{
+		"type":              "WebRtcEndpoint",
+		"constructorParams": map[string]interface{}{"mediaPipeline": "room"},
+	}, id)
+	s.subscribe(id, "IceCandidateFound", id+"/found")
+	s.subscribe(id, "IceGatheringDone", id+"/done")
+	if dispatched {
+		s.call("create", map[string]interface{}{
+			"type":              "HubPort",
+			"constructorParams": map[string]interface{}{"hub": "room/d"},
+		}, id+"/port")
+	}
+	if source != "" {
+		s.connect(source, id)
+	}
+	s.invoke(id, "processOffer", "answer "+id)
+	s.invoke(id, "gatherCandidates", true)
+}
+
+func (s *script) connect(source, sink string) {
+	s.call("invoke", map[string]interface{}{
+		"object":          source,
+		"operation":       "connect",
+		"operationParams": map[string]interface{}{"sink": sink},
+	}, nil)
+}
+
+// releaseBroadcastPeer expects the release of the endpoint of a peer, and of
+// its port if dispatched.
+func (s *script) releaseBroadcastPeer(id string, dispatched bool) {
+	s.unsubscribe(id)
+	s.unsubscribe(id)
+	if dispatched {
+		s.release(id + "/port")
+	}
+	s.release(id)
+}
+
+func TestBroadcast(t *testing.T) {
+	tests := []struct {
+		name       string
+		dispatched bool
+	}{
+		{"connected", false},
+		{"dispatched", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := tt.dispatched
+			setSource := func(s *script, presenter string) {
+				s.connect(presenter, presenter+"/port")
+				s.call("invoke", map[string]interface{}{
+					"object":          "room/d",
+					"operation":       "setSource",
+					"operationParams": map[string]interface{}{"source": presenter + "/port"},
+				}, nil)
+			}
+
+			s := &script{}
+			s.create("MediaPipeline", "room")
+			if d {
+				s.create("DispatcherOneToMany", "room/d")
+			}
+			s.broadcastPeer("room/alice", d, "")
+			if d {
+				setSource(s, "room/alice")
+				s.broadcastPeer("room/bob", d, "room/bob/port")
+			} else {
+				s.broadcastPeer("room/bob", d, "room/alice")
+			}
+			// a new presenter
+			s.broadcastPeer("room/dave", d, "")
+			if d {
+				setSource(s, "room/dave")
+			} else {
+				s.connect("room/dave", "room/bob")
+			}
+			s.releaseBroadcastPeer("room/alice", d)
+			// the presenter leaves
+			s.releaseBroadcastPeer("room/bob", d)
+			if d {
+				s.invoke("room/d", "removeSource", nil)
+			}
+			s.releaseBroadcastPeer("room/dave", d)
+			s.release("room")
+			conn, srv := s.serve(t)
+
+			var b *kurento.Broadcast
+			var released []string
+			b, err := kurento.NewBroadcast(conn, kurento.BroadcastOptions{
+				MaxViewers:     1,
+				UseDispatcher:  d,
+				OnIceCandidate: func(string, kurento.IceCandidate) error { return nil },
+				// the callbacks may call the broadcast
+				OnViewerReleased: func(id string) { released = append(released, id+fmt.Sprint(b.Viewers())) },
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			answer := func(string) error { return nil }
+			// the viewer is registered before its offer is accepted
+			viewerAnswer := func(string) error {
+				if fmt.Sprint(b.Viewers()) != "[room/bob]" {
+					t.Errorf("viewers when answering: %v", b.Viewers())
+				}
+				return nil
+			}
+			steps := []struct {
+				name   string
+				action func() error
+				want   error
+			}{
+				{"viewer without presenter", func() error { return b.AddViewer("bob", "offer", answer) }, kurento.ErrNoPresenter},
+				{"presenter", func() error { return b.SetPresenter("room/alice", "offer", answer) }, nil},
+				{"viewer", func() error { return b.AddViewer("room/bob", "offer", viewerAnswer) }, nil},
+				{"presenter as viewer", func() error { return b.AddViewer("room/alice", "offer", answer) }, kurento.ErrPeerExists},
+				{"viewer as presenter", func() error { return b.SetPresenter("room/bob", "offer", answer) }, kurento.ErrPeerExists},
+				{"full", func() error { return b.AddViewer("room/carol", "offer", answer) }, kurento.ErrRoomFull},
+				{"new presenter", func() error { return b.SetPresenter("room/dave", "offer", answer) }, nil},
+				{"unknown peer", func() error { return b.AddIceCandidate("room/alice", kurento.IceCandidate{}) }, kurento.ErrPeerNotFound},
+				{"presenter leaves", b.RemovePresenter, nil},
+				{"no presenter", b.RemovePresenter, kurento.ErrNoPresenter},
+				{"release", b.Release, nil},
+				{"release again", b.Release, nil},
+				{"presenter after release", func() error { return b.SetPresenter("room/alice", "offer", answer) }, kurento.ErrRoomReleased},
+			}
+			for _, step := range steps {
+				if err := step.action(); !errors.Is(err, step.want) {
+					t.Fatalf("%s: err = %v, want %v", step.name, err, step.want)
+				}
+				if step.name == "new presenter" {
+					if id, ok := b.Presenter(); !ok || id != "room/dave" || fmt.Sprint(b.Viewers()) != "[room/bob]" {
+						t.Errorf("presenter %s, viewers %v", id, b.Viewers())
+					}
+				}
+			}
+			done(t, srv)
+			if fmt.Sprint(released) != "[room/bob[]]" {
+				t.Errorf("released viewers %v", released)
+			}
+		})
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// generator renders the Go sources of a set of descriptors.
+type generator struct {
+	pkg   string
+	out   string
+	tests bool
+	reg   *registry
+	tpl   *template.Template
+}
+
+func newGenerator(pkg, out string, tests bool, files []*kmdFile) *generator {
+	g := &generator{
+		pkg:   pkg,
+		out:   out,
+		tests: tests,
+		reg:   newRegistry(files),
+	}
+	g.tpl = template.New("kurento").Funcs(template.FuncMap{
+		"comment": comment,
+	})
+	for name, body := range map[string]string{
+		"header":    headerTemplate,
+		"class":     classTemplate,
+		"enum":      enumTemplate,
+		"struct":    structTemplate,
+		"event":     eventTemplate,
+		"subscribe": subscribeTemplate,
+		"registry":  registryTemplate,
+		"test":      testTemplate,
+	} {
+		template.Must(g.tpl.New(name).Parse(body))
+	}
+	return g
+}
+
+// comment turns a KMD doc string into the body of a Go comment. The leading
+// "// " of the first line is expected in the template.
+func comment(doc string) string {
+	doc = strings.ReplaceAll(doc, ":rom:cls:", "")
+	doc = strings.TrimSpace(doc)
+	lines := strings.Split(doc, "\n")
+	for i, l := range lines {
+		l = strings.TrimRight(l, " \t")
+		if l == "" {
+			lines[i] = "//"
+			continue
+		}
+		lines[i] = "// " + l
+	}
+	return strings.Join(lines, "\n")
+}
+
+type paramView struct {
+	Name   string
+	Var    string
+	Type   string
+	Direct bool
+}
+
+type methodView struct {
+	Doc        string
+	Name       string
+	Operation  string
+	Params     []paramView
+	ReturnType string
+	ReturnDoc  string
+	ReturnKind string
+	ElemType   string
+	Zero       string
+}
+
+func (m methodView) Signature() string {
+	var args []string
+	for _, p := range m.Params {
+		args = append(args, p.Var+" "+p.Type)
+	}
+	ret := "error"
+	if m.ReturnType != "" {
+		ret = "(" + m.ReturnType + ", error)"
+	}
+	return fmt.Sprintf("%s(%s) %s", m.Name, strings.Join(args, ", "), ret)
+}
+
+type fieldView struct {
+	Doc       string
+	Name      string
+	Field     string
+	Type      string
+	Serialize string
+	Optional  bool
+}
+
+type ctorParamView struct {
+	Name  string
+	Value string
+}
+
+type classView struct {
+	Doc               string
+	Name              string
+	Extends           string
+	Properties        []fieldView
+	ConstructorParams []ctorParamView
+	Methods           []methodView
+	Accessors         []methodView
+	Events            []string
+}
+
+func (g *generator) method(m *method) methodView {
+	v := methodView{
+		Doc:       m.Doc,
+		Name:      upperFirst(m.Name),
+		Operation: m.Name,
+	}
+	for _, p := range m.Params {
+		k := parseType(p.Type)
+		v.Params = append(v.Params, paramView{
+			Name: p.Name,
+			Var:  p.Name,
+			Type: g.reg.paramType(p.Type),
+			// required numbers and booleans are sent even when zero, e.g. a
+			// position or a data channel ID, as are optional ones with a
+			// default value, which the caller gives when it has no other
+			Direct: (!p.Optional || p.DefaultValue != nil) && !k.IsList && !k.IsMap && g.reg.isPrimitive(k.Name) && k.Name != "String",
+		})
+	}
+	if m.Return != nil && m.Return.Type != "" {
+		g.setReturn(&v, m.Return.Type)
+		v.ReturnDoc = m.Return.Doc
+	}
+	return v
+}
+
+func (g *generator) setReturn(v *methodView, t string) {
+	v.ReturnType = g.reg.returnType(t)
+	v.ReturnKind = g.reg.returnKind(t)
+	v.Zero = g.reg.zeroValue(t)
+	k := parseType(t)
+	v.ElemType = g.reg.baseType(k.Name, false)
+}
+
+// accessors builds the getter and setter of a property. Accessors whose name
+// collide with a declared method are skipped.
+func (g *generator) accessors(c *remoteClass, p *property) []methodView {
+	declared := make(map[string]bool)
+	for _, m := range c.Methods {
+		declared[m.Name] = true
+	}
+
+	var ret []methodView
+	getter := "get" + upperFirst(p.Name)
+	if !declared[getter] {
+		v := methodView{
+			Doc:       fmt.Sprintf("Get%s returns the value of the %s property.", upperFirst(p.Name), p.Name),
+			Name:      upperFirst(getter),
+			Operation: getter,
+		}
+		g.setReturn(&v, p.Type)
+		ret = append(ret, v)
+	}
+
+	setter := "set" + upperFirst(p.Name)
+	if !p.ReadOnly && !p.Final && !declared[setter] {
+		k := parseType(p.Type)
+		ret = append(ret, methodView{
+			Doc:       fmt.Sprintf("Set%s changes the value of the %s property.", upperFirst(p.Name), p.Name),
+			Name:      upperFirst(setter),
+			Operation: setter,
+			Params: []paramView{{
+				Name: p.Name,
+				Var:  "value",
+				Type: g.reg.paramType(p.Type),
+				// zero values are meaningful for setters, so they are sent as is
+				Direct: !k.IsList && !k.IsMap && g.reg.isPrimitive(k.Name) && k.Name != "String",
+			}},
+		})
+	}
+	return ret
+}
+
+func (g *generator) class(c *remoteClass) classView {
+	v := classView{
+		Doc:     c.Doc,
+		Name:    c.Name,
+		Extends: c.Extends,
+	}
+	for _, p := range c.Properties {
+		v.Properties = append(v.Properties, fieldView{
+			Doc:   p.Doc,
+			Name:  p.Name,
+			Field: upperFirst(p.Name),
+			Type:  g.reg.fieldType(p.Type),
+		})
+	}
+	if c.Constructor != nil {
+		for _, p := range c.Constructor.Params {
+			if lit, ok := g.reg.defaultLiteral(p); ok {
+				v.ConstructorParams = append(v.ConstructorParams, ctorParamView{Name: p.Name, Value: lit})
+			}
+		}
+	}
+	for _, m := range c.Methods {
+		v.Methods = append(v.Methods, g.method(m))
+	}
+	for _, p := range c.Properties {
+		v.Accessors = append(v.Accessors, g.accessors(c, p)...)
+	}
+	return v
+}
+
+func (g *generator) render(name string, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := g.tpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func (g *generator) write(file string, src []byte) error {
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w\n%s", file, err, src)
+	}
+	return os.WriteFile(filepath.Join(g.out, file), formatted, 0o644)
+}
+
+// classFile renders the remote classes and events declared in a descriptor.
+func (g *generator) classFile(k *kmdFile) error {
+	var body bytes.Buffer
+	needFmt := false
+
+	for _, c := range k.RemoteClasses {
+		v := g.class(c)
+		if len(v.Methods) > 0 || len(v.Accessors) > 0 {
+			needFmt = true
+		}
+		for _, p := range v.ConstructorParams {
+			if strings.HasPrefix(p.Value, "fmt.") {
+				needFmt = true
+			}
+		}
+		// accessors are rendered as regular methods, after the declared ones,
+		// but are not part of the interface
+		all := v
+		all.Methods = append(append([]methodView{}, v.Methods...), v.Accessors...)
+		for _, e := range c.Events {
+			s, err := g.render("subscribe", map[string]string{"Class": c.Name, "Event": e})
+			if err != nil {
+				return err
+			}
+			all.Events = append(all.Events, s)
+		}
+		s, err := g.render("class", struct {
+			classView
+			Interface []methodView
+		}{all, v.Methods})
+		if err != nil {
+			return err
+		}
+		body.WriteString(s)
+	}
+
+	for _, e := range k.Events {
+		var props []fieldView
+		for _, p := range e.Properties {
+			props = append(props, fieldView{
+				Doc:   p.Doc,
+				Name:  p.Name,
+				Field: upperFirst(p.Name),
+				Type:  g.reg.eventFieldType(p.Type),
+			})
+		}
+		s, err := g.render("event", map[string]interface{}{
+			"Doc":        e.Doc,
+			"Name":       e.Name,
+			"Extends":    e.Extends,
+			"Properties": props,
+		})
+		if err != nil {
+			return err
+		}
+		body.WriteString(s)
+	}
+
+	if body.Len() == 0 {
+		return nil
+	}
+	header, err := g.render("header", map[string]interface{}{"Package": g.pkg, "NeedFmt": needFmt})
+	if err != nil {
+		return err
+	}
+	return g.write(k.goFileName(), []byte(header+body.String()))
+}
+
+// complexFile renders one complex type in its own file.
+func (g *generator) complexFile(module string, t *complexType) error {
+	var s string
+	var err error
+	if t.IsEnum() {
+		type value struct{ Const, Value string }
+		var values []value
+		for _, val := range t.Values {
+			values = append(values, value{enumConst(t.Name, val), val})
+		}
+		s, err = g.render("enum", map[string]interface{}{"Doc": t.Doc, "Name": t.Name, "Values": values})
+	} else {
+		var props []fieldView
+		for _, p := range t.Properties {
+			k := parseType(p.Type)
+			typ := g.reg.paramType(p.Type)
+			if g.reg.isClass(k.Name) {
+				// remote objects are decoded from their ID
+				typ = g.reg.wrap(k, k.Name)
+			}
+			ser := "t." + upperFirst(p.Name)
+			single := !k.IsList && !k.IsMap
+			if _, ok := g.reg.complex[k.Name]; ok && single && !g.reg.isEnum(k.Name) && !p.Optional {
+				// optional complex members are serialized by setIfNotEmpty
+				ser += ".CustomSerialize()"
+			}
+			if g.reg.isClass(k.Name) && single {
+				// remote objects are sent by ID
+				ser += ".String()"
+			}
+			props = append(props, fieldView{
+				Name:      p.Name,
+				Field:     upperFirst(p.Name),
+				Type:      typ,
+				Serialize: ser,
+				// optional members are left out when empty
+				Optional: p.Optional,
+			})
+		}
+		s, err = g.render("struct", map[string]interface{}{
+			"Doc":        t.Doc,
+			"Name":       t.Name,
+			"Extends":    t.Extends,
+			"Properties": props,
+			"Module":     serializedModule(module),
+		})
+	}
+	if err != nil {
+		return err
+	}
+	header, err := g.render("header", map[string]interface{}{"Package": g.pkg})
+	if err != nil {
+		return err
+	}
+	return g.write("complexTypes_"+t.Name+".go", []byte(header+s))
+}
+
+// serializedModule is the "__module__" sent with complex types. Types of
+// the modules shipped with KMS are registered under "kurento".
+func serializedModule(module string) string {
+	switch module {
+	case "core", "elements", "filters":
+		return "kurento"
+	}
+	return module
+}
+
+// registryFile renders the factory used to hydrate objects from their ID.
+func (g *generator) registryFile() error {
+	var classes []string
+	for _, name := range g.reg.ordering {
+		if !g.reg.classes[name].Abstract {
+			classes = append(classes, name)
+		}
+	}
+	sort.Strings(classes)
+	s, err := g.render("registry", map[string]interface{}{"Package": g.pkg, "Classes": classes})
+	if err != nil {
+		return err
+	}
+	return g.write("registry.go", []byte(s))
+}
+
+// testFile renders constructor tests of the classes of a descriptor.
+func (g *generator) testFile(k *kmdFile) error {
+	type testView struct {
+		Name         string
+		ParentParams []string
+	}
+	var classes []testView
+	for _, c := range k.RemoteClasses {
+		if c.Abstract || c.Constructor == nil {
+			continue
+		}
+		t := testView{Name: c.Name}
+		for _, p := range c.Constructor.Params {
+			if g.reg.isClass(parseType(p.Type).Name) {
+				t.ParentParams = append(t.ParentParams, p.Name)
+			}
+		}
+		classes = append(classes, t)
+	}
+	if len(classes) == 0 {
+		return nil
+	}
+	s, err := g.render("test", map[string]interface{}{"Package": g.pkg, "Classes": classes})
+	if err != nil {
+		return err
+	}
+	return g.write(strings.TrimSuffix(k.goFileName(), ".go")+"_test.go", []byte(s))
+}
+
+func (g *generator) run(files []*kmdFile) error {
+	for _, k := range files {
+		if err := g.classFile(k); err != nil {
+			return err
+		}
+		for _, t := range k.ComplexTypes {
+			if err := g.complexFile(k.Name, t); err != nil {
+				return err
+			}
+		}
+		if g.tests {
+			if err := g.testFile(k); err != nil {
+				return err
+			}
+		}
+	}
+	return g.registryFile()
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files of testdata/golden")
+
+// TestGolden generates the descriptors of testdata/kmd, with the tests, and
+// compares the output with testdata/golden byte for byte.
+func TestGolden(t *testing.T) {
+	files, err := loadKmd([]string{filepath.Join("testdata", "kmd")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := t.TempDir()
+	if *update {
+		out = filepath.Join("testdata", "golden")
+	}
+	if err := newGenerator("kurento", out, true, files).run(files); err != nil {
+		t.Fatal(err)
+	}
+
+	got := readDir(t, out)
+	want := readDir(t, filepath.Join("testdata", "golden"))
+	for name, src := range got {
+		expected, ok := want[name]
+		if !ok {
+			t.Errorf("%s: unexpected file", name)
+			continue
+		}
+		if !bytes.Equal(src, expected) {
+			t.Errorf("%s differs from the golden file, run go test -update to rewrite it\n%s", name, src)
+		}
+	}
+	for name := range want {
+		if _, ok := got[name]; !ok {
+			t.Errorf("%s: not generated", name)
+		}
+	}
+}
+
+func readDir(t *testing.T, dir string) map[string][]byte {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := make(map[string][]byte)
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ret[e.Name()] = data
+	}
+	return ret
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		in   string
+		want kmdType
+	}{
+		{"String", kmdType{Name: "String"}},
+		{"MediaElement[]", kmdType{Name: "MediaElement", IsList: true}},
+		{"int<>", kmdType{Name: "int", IsMap: true}},
+	}
+	for _, tt := range tests {
+		if got := parseType(tt.in); got != tt.want {
+			t.Errorf("parseType(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGoFileName(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"kmd/core.kmd.json", "core.go"},
+		{"kmd/elements.WebRtcEndpoint.kmd.json", "WebRtcEndpoint.go"},
+		{"filters.kmd.json", "filters.go"},
+	}
+	for _, tt := range tests {
+		if got := (&kmdFile{path: tt.path}).goFileName(); got != tt.want {
+			t.Errorf("goFileName(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestTypes(t *testing.T) {
+	files, err := loadKmd([]string{filepath.Join("testdata", "kmd")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reg := newRegistry(files)
+
+	tests := []struct {
+		kmd        string
+		param      string
+		returnKind string
+	}{
+		{"String", "string", "primitive"},
+		{"int", "int", "number"},
+		{"double", "float64", "primitive"},
+		{"MediaElement", "IMediaElement", "object"},
+		{"MediaElement[]", "[]IMediaElement", "objects"},
+		{"MediaType", "MediaType", "decode"},
+		{"Tag[]", "[]Tag", "decode"},
+	}
+	for _, tt := range tests {
+		if got := reg.paramType(tt.kmd); got != tt.param {
+			t.Errorf("paramType(%q) = %q, want %q", tt.kmd, got, tt.param)
+		}
+		if got := reg.returnKind(tt.kmd); got != tt.returnKind {
+			t.Errorf("returnKind(%q) = %q, want %q", tt.kmd, got, tt.returnKind)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// kmdFile is the root of a Kurento Module Descriptor (.kmd.json) file.
+type kmdFile struct {
+	Name           string         `json:"name"`
+	Version        string         `json:"version"`
+	KurentoVersion string         `json:"kurentoVersion"`
+	RemoteClasses  []*remoteClass `json:"remoteClasses"`
+	ComplexTypes   []*complexType `json:"complexTypes"`
+	Events         []*event       `json:"events"`
+
+	// path of the file, used to name the generated Go file
+	path string
+}
+
+type remoteClass struct {
+	Name        string      `json:"name"`
+	Doc         string      `json:"doc"`
+	Extends     string      `json:"extends"`
+	Abstract    bool        `json:"abstract"`
+	Constructor *method     `json:"constructor"`
+	Properties  []*property `json:"properties"`
+	Methods     []*method   `json:"methods"`
+	Events      []string    `json:"events"`
+}
+
+type method struct {
+	Name   string      `json:"name"`
+	Doc    string      `json:"doc"`
+	Params []*param    `json:"params"`
+	Return *returnType `json:"return"`
+}
+
+type param struct {
+	Name         string      `json:"name"`
+	Doc          string      `json:"doc"`
+	Type         string      `json:"type"`
+	Optional     bool        `json:"optional"`
+	DefaultValue interface{} `json:"defaultValue"`
+}
+
+type returnType struct {
+	Doc  string `json:"doc"`
+	Type string `json:"type"`
+}
+
+type property struct {
+	Name         string      `json:"name"`
+	Doc          string      `json:"doc"`
+	Type         string      `json:"type"`
+	ReadOnly     bool        `json:"readOnly"`
+	Final        bool        `json:"final"`
+	Optional     bool        `json:"optional"`
+	DefaultValue interface{} `json:"defaultValue"`
+}
+
+type complexType struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc"`
+	TypeFormat string      `json:"typeFormat"`
+	Extends    string      `json:"extends"`
+	Values     []string    `json:"values"`
+	Properties []*property `json:"properties"`
+}
+
+// IsEnum reports if the type is an enumeration.
+func (t *complexType) IsEnum() bool {
+	return t.TypeFormat == "ENUM"
+}
+
+type event struct {
+	Name       string      `json:"name"`
+	Doc        string      `json:"doc"`
+	Extends    string      `json:"extends"`
+	Properties []*property `json:"properties"`
+}
+
+// loadKmd reads every .kmd.json file given in paths. Directories are walked
+// (non recursively) for files with the .kmd.json extension.
+func loadKmd(paths []string) ([]*kmdFile, error) {
+	var files []string
+	for _, p := range paths {
+		st, err := os.Stat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !st.IsDir() {
+			files = append(files, p)
+			continue
+		}
+		matches, err := filepath.Glob(filepath.Join(p, "*.kmd.json"))
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(matches)
+		files = append(files, matches...)
+	}
+
+	var ret []*kmdFile
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		k := &kmdFile{path: f}
+		if err := json.Unmarshal(data, k); err != nil {
+			return nil, fmt.Errorf("%s: %w", f, err)
+		}
+		ret = append(ret, k)
+	}
+	return ret, nil
+}
+
+// goFileName returns the name of the Go file generated for the classes and
+// events of this descriptor. The module prefix is removed from the file
+// name, so "elements.WebRtcEndpoint.kmd.json" gives "WebRtcEndpoint.go"
+// while "core.kmd.json" gives "core.go".
+func (k *kmdFile) goFileName() string {
+	base := strings.TrimSuffix(filepath.Base(k.path), ".kmd.json")
+	if i := strings.Index(base, "."); i >= 0 {
+		base = base[i+1:]
+	}
+	return base + ".go"
+}
@@ -0,0 +1,54 @@
+// Command kurento-gen generates the Go client of the Kurento Media Server API
+// from Kurento Module Descriptor files (.kmd.json).
+//
+// Each descriptor gives one Go file holding its remote classes and events,
+// named after the descriptor without its module prefix ("core.kmd.json"
+// gives "core.go", "elements.WebRtcEndpoint.kmd.json" gives
+// "WebRtcEndpoint.go"). Complex types are written to their own
+// "complexTypes_<Name>.go" file, and "registry.go" lists every concrete class
+// so objects can be rebuilt from their ID.
+//
+// Usage:
+//
+//	kurento-gen [-out dir] [-package name] [-tests] file.kmd.json|dir ...
+//
+// Descriptors of every module must be given in the same run, so types can be
+// resolved across modules.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	out := flag.String("out", ".", "output directory")
+	pkg := flag.String("package", "kurento", "name of the generated package")
+	tests := flag.Bool("tests", false, "also generate constructor tests")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] file.kmd.json|dir ...\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	files, err := loadKmd(flag.Args())
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "kurento-gen:", err)
+		os.Exit(1)
+	}
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "kurento-gen: no descriptor found")
+		os.Exit(1)
+	}
+
+	if err := newGenerator(*pkg, *out, *tests, files).run(files); err != nil {
+		fmt.Fprintln(os.Stderr, "kurento-gen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+const headerTemplate = `// Code generated by kurento-gen. DO NOT EDIT.
+
+package {{.Package}}
+{{if .NeedFmt}}
+import "fmt"
+{{end}}`
+
+const classTemplate = `
+{{- if .Extends}}
+type I{{.Name}} interface {
+{{- range .Interface}}
+	{{.Signature}}
+{{- end}}
+}
+{{- end}}
+
+{{comment .Doc}}
+type {{.Name}} struct {
+{{- if .Extends}}
+	{{.Extends}}
+{{- else}}
+	connection *Connection
+{{- end}}
+{{- range .Properties}}
+
+	{{comment .Doc}}
+	{{.Field}} {{.Type}}
+{{- end}}
+}
+
+// Return contructor params to be called by "Create".
+func (elem *{{.Name}}) getConstructorParams(from IMediaObject, options map[string]interface{}) map[string]interface{} {
+{{- if .ConstructorParams}}
+
+	// Create basic constructor params
+	ret := map[string]interface{}{
+{{- range .ConstructorParams}}
+		"{{.Name}}": {{.Value}},
+{{- end}}
+	}
+
+	// then merge options
+	mergeOptions(ret, options)
+
+	return ret
+{{else}}
+	return options
+{{end}}
+}
+{{range .Methods}}
+{{comment .Doc}}
+{{- if .ReturnDoc}}
+// Returns:
+// {{comment .ReturnDoc}}
+{{- end}}
+func (elem *{{$.Name}}) {{.Signature}} {
+	req := elem.getInvokeRequest()
+{{if .Params}}
+	params := make(map[string]interface{})
+{{range .Params}}
+{{- if .Direct}}
+	params["{{.Name}}"] = {{.Var}}
+{{- else}}
+	setIfNotEmpty(params, "{{.Name}}", {{.Var}})
+{{- end}}
+{{- end}}
+
+	reqparams := map[string]interface{}{
+		"operation":       "{{.Operation}}",
+		"object":          elem.Id,
+		"operationParams": params,
+	}
+{{- else}}
+	reqparams := map[string]interface{}{
+		"operation": "{{.Operation}}",
+		"object":    elem.Id,
+	}
+{{- end}}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	// Call server and wait response
+	response := <-elem.connection.Request(req)
+{{if not .ReturnType}}
+	// Returns error or nil
+	if response.Error != nil {
+		return fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+	return nil
+{{else}}
+	{{- if .ReturnDoc}}
+	// {{comment .ReturnDoc}}
+	{{- end}}
+	var err error
+	if response.Error != nil {
+		err = fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+{{if eq .ReturnKind "primitive"}}
+	if value, ok := response.Result["value"].({{.ReturnType}}); ok {
+		return value, err
+	}
+
+	return {{.Zero}}, err
+{{- else if eq .ReturnKind "number"}}
+	if value, ok := response.Result["value"].(float64); ok {
+		return {{.ReturnType}}(value), err
+	}
+
+	return {{.Zero}}, err
+{{- else if eq .ReturnKind "object"}}
+	for _, obj := range hydrateMediaObjects(elem.connection, response.Result["value"]) {
+		if ret, ok := obj.({{.ReturnType}}); ok {
+			return ret, err
+		}
+	}
+
+	return nil, err
+{{- else if eq .ReturnKind "objects"}}
+	ret := {{.Zero}}
+	for _, obj := range hydrateMediaObjects(elem.connection, response.Result["value"]) {
+		if o, ok := obj.({{.ElemType}}); ok {
+			ret = append(ret, o)
+		}
+	}
+
+	return ret, err
+{{- else}}
+	{{- if or (eq .Zero "nil") (eq .Zero "\"\"")}}
+	var ret {{.ReturnType}}
+	{{- else}}
+	ret := {{.Zero}}
+	{{- end}}
+	if value, ok := response.Result["value"]; ok && err == nil {
+		err = decodeValue(elem.connection, value, &ret)
+	}
+
+	return ret, err
+{{- end}}
+{{end}}
+}
+{{end}}
+{{- range .Events}}
+{{.}}
+{{end}}`
+
+const enumTemplate = `
+{{comment .Doc}}
+type {{.Name}} string
+
+// Implement fmt.Stringer interface
+func (t {{.Name}}) String() string {
+	return string(t)
+}
+
+const (
+{{- range .Values}}
+	{{.Const}} {{$.Name}} = "{{.Value}}"
+{{- end}}
+)
+`
+
+const structTemplate = `
+{{- if .Doc}}
+{{comment .Doc}}
+{{- end}}
+type {{.Name}} struct {
+{{- if .Extends}}
+	{{.Extends}}
+{{- end}}
+{{- range .Properties}}
+	{{.Field}} {{.Type}}
+{{- end}}
+}
+
+func (t {{.Name}}) CustomSerialize() map[string]interface{} {
+	ret := make(map[string]interface{})
+{{- if .Extends}}
+	for k, v := range t.{{.Extends}}.CustomSerialize() {
+		ret[k] = v
+	}
+{{- end}}
+{{- range .Properties}}
+{{- if .Optional}}
+	setIfNotEmpty(ret, "{{.Name}}", {{.Serialize}})
+{{- else}}
+	ret["{{.Name}}"] = {{.Serialize}}
+{{- end}}
+{{- end}}
+	ret["__type__"] = "{{.Name}}"
+	ret["__module__"] = "{{.Module}}"
+	return ret
+}
+`
+
+const eventTemplate = `
+{{comment .Doc}}
+type {{.Name}}Event struct {
+{{- if .Extends}}
+	{{.Extends}}Event
+{{- end}}
+{{- range $i, $p := .Properties}}
+{{- if or $i $.Extends}}
+{{end}}
+	{{comment $p.Doc}}
+	{{$p.Field}} {{$p.Type}}
+{{- end}}
+}
+`
+
+const subscribeTemplate = `
+// Subscribe{{.Event}} registers cb to be called for every {{.Event}} event
+// fired by this object. It returns the handler ID of the subscription.
+func (elem *{{.Class}}) Subscribe{{.Event}}(cb func({{.Event}}Event)) (string, error) {
+	return elem.Subscribe("{{.Event}}", func(data map[string]interface{}) {
+		ev := {{.Event}}Event{}
+		if err := decodeValue(elem.connection, data, &ev); err == nil {
+			cb(ev)
+		}
+	})
+}
+`
+
+const registryTemplate = `// Code generated by kurento-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+// NewMediaObject returns an empty object of the given Kurento type, or nil if
+// the type is unknown or abstract.
+func NewMediaObject(typeName string) IMediaObject {
+	switch typeName {
+{{- range .Classes}}
+	case "{{.}}":
+		return &{{.}}{}
+{{- end}}
+	}
+	return nil
+}
+`
+
+const testTemplate = `// Code generated by kurento-gen. DO NOT EDIT.
+
+package {{.Package}}
+
+import "testing"
+{{range .Classes}}
+func Test{{.Name}}ConstructorParams(t *testing.T) {
+	from := &MediaObject{}
+	from.setId("parent")
+
+	params := (&{{.Name}}{}).getConstructorParams(from, map[string]interface{}{"extra": "value"})
+	if params["extra"] != "value" {
+		t.Errorf("options not merged: %v", params)
+	}
+{{- range .ParentParams}}
+	if params["{{.}}"] != "parent" {
+		t.Errorf("{{.}} = %v, want parent", params["{{.}}"])
+	}
+{{- end}}
+}
+{{end}}`
@@ -0,0 +1,175 @@
+// Code generated by kurento-gen. DO NOT EDIT.
+
+package kurento
+
+import "fmt"
+
+type ISampleEndpoint interface {
+	Restart(delay int, seamless bool, loops int) error
+	GetLoad() (float64, error)
+}
+
+// An endpoint sending a test pattern.
+type SampleEndpoint struct {
+	MediaElement
+
+	// Position of the pattern, in milliseconds.
+	Position int64
+}
+
+// Return contructor params to be called by "Create".
+func (elem *SampleEndpoint) getConstructorParams(from IMediaObject, options map[string]interface{}) map[string]interface{} {
+
+	// Create basic constructor params
+	ret := map[string]interface{}{
+		"mediaPipeline": fmt.Sprintf("%s", from),
+		"pattern":       "smpte",
+		"mediaType":     MEDIATYPE_VIDEO,
+	}
+
+	// then merge options
+	mergeOptions(ret, options)
+
+	return ret
+
+}
+
+// Restarts the pattern.
+func (elem *SampleEndpoint) Restart(delay int, seamless bool, loops int) error {
+	req := elem.getInvokeRequest()
+
+	params := make(map[string]interface{})
+
+	params["delay"] = delay
+	params["seamless"] = seamless
+	setIfNotEmpty(params, "loops", loops)
+
+	reqparams := map[string]interface{}{
+		"operation":       "restart",
+		"object":          elem.Id,
+		"operationParams": params,
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	// Call server and wait response
+	response := <-elem.connection.Request(req)
+
+	// Returns error or nil
+	if response.Error != nil {
+		return fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+	return nil
+
+}
+
+// Load of the endpoint.
+// Returns:
+// // Ratio between 0 and 1.
+func (elem *SampleEndpoint) GetLoad() (float64, error) {
+	req := elem.getInvokeRequest()
+
+	reqparams := map[string]interface{}{
+		"operation": "getLoad",
+		"object":    elem.Id,
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	// Call server and wait response
+	response := <-elem.connection.Request(req)
+
+	// // Ratio between 0 and 1.
+	var err error
+	if response.Error != nil {
+		err = fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+
+	if value, ok := response.Result["value"].(float64); ok {
+		return value, err
+	}
+
+	return 0, err
+
+}
+
+// GetPosition returns the value of the position property.
+func (elem *SampleEndpoint) GetPosition() (int64, error) {
+	req := elem.getInvokeRequest()
+
+	reqparams := map[string]interface{}{
+		"operation": "getPosition",
+		"object":    elem.Id,
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	// Call server and wait response
+	response := <-elem.connection.Request(req)
+
+	var err error
+	if response.Error != nil {
+		err = fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+
+	if value, ok := response.Result["value"].(float64); ok {
+		return int64(value), err
+	}
+
+	return 0, err
+
+}
+
+// SetPosition changes the value of the position property.
+func (elem *SampleEndpoint) SetPosition(value int64) error {
+	req := elem.getInvokeRequest()
+
+	params := make(map[string]interface{})
+
+	params["position"] = value
+
+	reqparams := map[string]interface{}{
+		"operation":       "setPosition",
+		"object":          elem.Id,
+		"operationParams": params,
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	// Call server and wait response
+	response := <-elem.connection.Request(req)
+
+	// Returns error or nil
+	if response.Error != nil {
+		return fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+	return nil
+
+}
+
+// SubscribePatternDone registers cb to be called for every PatternDone event
+// fired by this object. It returns the handler ID of the subscription.
+func (elem *SampleEndpoint) SubscribePatternDone(cb func(PatternDoneEvent)) (string, error) {
+	return elem.Subscribe("PatternDone", func(data map[string]interface{}) {
+		ev := PatternDoneEvent{}
+		if err := decodeValue(elem.connection, data, &ev); err == nil {
+			cb(ev)
+		}
+	})
+}
+
+// Raised when the pattern is complete.
+type PatternDoneEvent struct {
+	MediaEvent
+
+	// Number of loops played.
+	Loops int
+}
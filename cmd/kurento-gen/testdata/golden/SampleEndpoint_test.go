@@ -0,0 +1,18 @@
+// Code generated by kurento-gen. DO NOT EDIT.
+
+package kurento
+
+import "testing"
+
+func TestSampleEndpointConstructorParams(t *testing.T) {
+	from := &MediaObject{}
+	from.setId("parent")
+
+	params := (&SampleEndpoint{}).getConstructorParams(from, map[string]interface{}{"extra": "value"})
+	if params["extra"] != "value" {
+		t.Errorf("options not merged: %v", params)
+	}
+	if params["mediaPipeline"] != "parent" {
+		t.Errorf("mediaPipeline = %v, want parent", params["mediaPipeline"])
+	}
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// kmdType is a parsed KMD type reference. KMD writes lists as "Type[]" and
+// string keyed maps as "Type<>".
+type kmdType struct {
+	Name   string
+	IsList bool
+	IsMap  bool
+}
+
+func parseType(t string) kmdType {
+	switch {
+	case strings.HasSuffix(t, "[]"):
+		return kmdType{Name: strings.TrimSuffix(t, "[]"), IsList: true}
+	case strings.HasSuffix(t, "<>"):
+		return kmdType{Name: strings.TrimSuffix(t, "<>"), IsMap: true}
+	}
+	return kmdType{Name: t}
+}
+
+var primitives = map[string]string{
+	"String":  "string",
+	"boolean": "bool",
+	"int":     "int",
+	"int64":   "int64",
+	"float":   "float64",
+	"double":  "float64",
+}
+
+// registry knows every class, complex type and event declared by the loaded
+// descriptors, so types can be resolved across modules.
+type registry struct {
+	classes  map[string]*remoteClass
+	complex  map[string]*complexType
+	events   map[string]*event
+	ordering []string
+}
+
+func newRegistry(files []*kmdFile) *registry {
+	r := &registry{
+		classes: make(map[string]*remoteClass),
+		complex: make(map[string]*complexType),
+		events:  make(map[string]*event),
+	}
+	for _, f := range files {
+		for _, c := range f.RemoteClasses {
+			r.classes[c.Name] = c
+			r.ordering = append(r.ordering, c.Name)
+		}
+		for _, t := range f.ComplexTypes {
+			r.complex[t.Name] = t
+		}
+		for _, e := range f.Events {
+			r.events[e.Name] = e
+		}
+	}
+	return r
+}
+
+func (r *registry) isPrimitive(name string) bool {
+	_, ok := primitives[name]
+	return ok
+}
+
+func (r *registry) isClass(name string) bool {
+	_, ok := r.classes[name]
+	return ok
+}
+
+func (r *registry) isEnum(name string) bool {
+	t, ok := r.complex[name]
+	return ok && t.IsEnum()
+}
+
+func (r *registry) isNumeric(name string) bool {
+	switch primitives[name] {
+	case "int", "int64", "float64":
+		return true
+	}
+	return false
+}
+
+// baseType returns the Go type of a single value of the KMD type "name".
+// Remote classes are always referenced by their interface; complex types are
+// referenced by pointer when ptr is set.
+func (r *registry) baseType(name string, ptr bool) string {
+	if p, ok := primitives[name]; ok {
+		return p
+	}
+	if r.isClass(name) {
+		return "I" + name
+	}
+	if ptr {
+		return "*" + name
+	}
+	return name
+}
+
+func (r *registry) wrap(t kmdType, base string) string {
+	switch {
+	case t.IsList:
+		return "[]" + base
+	case t.IsMap:
+		return "map[string]" + base
+	}
+	return base
+}
+
+// paramType is the Go type of a method parameter.
+func (r *registry) paramType(t string) string {
+	k := parseType(t)
+	return r.wrap(k, r.baseType(k.Name, false))
+}
+
+// fieldType is the Go type of a struct field holding a property.
+func (r *registry) fieldType(t string) string {
+	k := parseType(t)
+	return r.wrap(k, r.baseType(k.Name, !r.isPrimitive(k.Name)))
+}
+
+// eventFieldType is the Go type of an event property. Remote objects are
+// sent by KMS as their ID, so they are kept as strings.
+func (r *registry) eventFieldType(t string) string {
+	k := parseType(t)
+	if r.isClass(k.Name) {
+		return r.wrap(k, "string")
+	}
+	return r.wrap(k, r.baseType(k.Name, false))
+}
+
+// returnType is the Go type returned by a method.
+func (r *registry) returnType(t string) string {
+	k := parseType(t)
+	return r.wrap(k, r.baseType(k.Name, false))
+}
+
+// returnKind tells the templates how to decode the "value" of a response.
+func (r *registry) returnKind(t string) string {
+	k := parseType(t)
+	switch {
+	case r.isClass(k.Name) && (k.IsList || k.IsMap):
+		return "objects"
+	case r.isClass(k.Name):
+		return "object"
+	case k.IsList || k.IsMap:
+		return "decode"
+	case r.isNumeric(k.Name) && primitives[k.Name] != "float64":
+		// JSON numbers are decoded as float64
+		return "number"
+	case r.isPrimitive(k.Name):
+		return "primitive"
+	}
+	return "decode"
+}
+
+// zeroValue returns the literal of an empty value of the return type.
+func (r *registry) zeroValue(t string) string {
+	k := parseType(t)
+	if k.IsList || k.IsMap {
+		return r.returnType(t) + "{}"
+	}
+	switch primitives[k.Name] {
+	case "string":
+		return `""`
+	case "bool":
+		return "false"
+	case "int", "int64", "float64":
+		return "0"
+	}
+	if r.isClass(k.Name) {
+		return "nil"
+	}
+	if r.isEnum(k.Name) {
+		return `""`
+	}
+	return r.returnType(t) + "{}"
+}
+
+// defaultLiteral returns the Go literal of a constructor parameter default
+// value. ok is false if the parameter must not be sent when the caller does
+// not provide it.
+func (r *registry) defaultLiteral(p *param) (lit string, ok bool) {
+	k := parseType(p.Type)
+	if r.isClass(k.Name) {
+		return `fmt.Sprintf("%s", from)`, true
+	}
+	if p.DefaultValue != nil {
+		if r.isEnum(k.Name) && !k.IsList && !k.IsMap {
+			return enumConst(k.Name, fmt.Sprint(p.DefaultValue)), true
+		}
+		if k.IsList || k.IsMap || !r.isPrimitive(k.Name) {
+			// complex defaults are left to the server
+			return "", false
+		}
+		if s, isString := p.DefaultValue.(string); isString {
+			return fmt.Sprintf("%q", s), true
+		}
+		return fmt.Sprint(p.DefaultValue), true
+	}
+	if p.Optional || k.IsList || k.IsMap {
+		return "", false
+	}
+	switch primitives[k.Name] {
+	case "string":
+		return `""`, true
+	case "bool":
+		return "false", true
+	case "int", "int64", "float64":
+		return "0", true
+	}
+	return "", false
+}
+
+// enumConst is the name of the constant generated for an enum value.
+func enumConst(typeName, value string) string {
+	return strings.ToUpper(typeName) + "_" + value
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,509 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/graph"
+)
+
+// defaultEvents are the events watched when none are given.
+var defaultEvents = []string{
+	"Error", "ElementConnected", "ElementDisconnected",
+	"MediaFlowInStateChanged", "MediaFlowOutStateChanged", "MediaTranscodingStateChanged",
+	"MediaStateChanged", "ConnectionStateChanged", "IceCandidateFound", "IceGatheringDone",
+	"IceComponentStateChanged", "NewCandidatePairSelected", "DataChannelOpen", "DataChannelClose",
+	"EndOfStream", "UriEndpointStateChanged", "Recording", "Paused", "Stopped",
+}
+
+type cli struct {
+	conn *kurento.Connection
+	json bool
+	out  io.Writer
+}
+
+// print writes v as JSON, or calls table to write it as a table.
+func (c *cli) print(v interface{}, table func(w io.Writer)) error {
+	if c.json {
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+// parse parses the flags of a command, which takes nargs arguments, or at
+// least one if nargs is negative.
+func parse(fs *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	switch {
+	case nargs < 0 && fs.NArg() == 0:
+		return nil, fmt.Errorf("%s: missing object ID", fs.Name())
+	case nargs >= 0 && fs.NArg() != nargs:
+		return nil, fmt.Errorf("%s: expected %d arguments, got %d", fs.Name(), nargs, fs.NArg())
+	}
+	return fs.Args(), nil
+}
+
+// shortId returns the UUID of the last component of an ID.
+func shortId(id string) string {
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	if i := strings.Index(id, "_"); i >= 0 {
+		id = id[:i]
+	}
+	return id
+}
+
+func formatTags(tags map[string]string) string {
+	pairs := make([]string, 0, len(tags))
+	for k, v := range tags {
+		pairs = append(pairs, k+"="+v)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ",")
+}
+
+func (c *cli) object(id string) *kurento.MediaObject {
+	obj := &kurento.MediaObject{}
+	kurento.HydrateMediaObject(id, nil, c.conn, obj)
+	return obj
+}
+
+type objectInfo struct {
+	Id       string            `json:"id"`
+	Type     string            `json:"type"`
+	Name     string            `json:"name"`
+	Created  time.Time         `json:"created"`
+	Tags     map[string]string `json:"tags"`
+	Children []objectInfo      `json:"children,omitempty"`
+
+	childCount int
+}
+
+// describe reads the properties of an object, and of its descendants if
+// recurse is true.
+func (c *cli) describe(id string, recurse bool) (objectInfo, error) {
+	obj := c.object(id)
+	info := objectInfo{Id: id, Type: kurento.TypeFromId(id), Tags: make(map[string]string)}
+
+	var err error
+	if info.Name, err = obj.GetName(); err != nil {
+		return info, err
+	}
+	created, err := obj.GetCreationTime()
+	if err != nil {
+		return info, err
+	}
+	info.Created = time.Unix(int64(created), 0)
+	tags, err := obj.GetTags()
+	if err != nil {
+		return info, err
+	}
+	for _, tag := range tags {
+		info.Tags[tag.Key] = tag.Value
+	}
+	children, err := obj.GetChildren()
+	if err != nil {
+		return info, err
+	}
+	info.childCount = len(children)
+	if recurse {
+		for _, child := range children {
+			childInfo, err := c.describe(child.String(), true)
+			if err != nil {
+				return info, err
+			}
+			info.Children = append(info.Children, childInfo)
+		}
+	}
+	return info, nil
+}
+
+func (c *cli) info(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	manager := c.conn.ServerManager()
+	info, err := manager.GetInfo()
+	if err != nil {
+		return err
+	}
+	out := struct {
+		Version      string   `json:"version"`
+		Type         string   `json:"type"`
+		Capabilities []string `json:"capabilities"`
+		CpuCount     int      `json:"cpuCount"`
+		UsedCpu      float64  `json:"usedCpu"`
+		UsedMemory   int64    `json:"usedMemoryKiB"`
+		Metadata     string   `json:"metadata,omitempty"`
+	}{Version: info.Version, Type: string(info.Type), Capabilities: info.Capabilities}
+	if out.CpuCount, err = manager.GetCpuCount(); err != nil {
+		return err
+	}
+	if out.UsedCpu, err = manager.GetUsedCpu(1000); err != nil {
+		return err
+	}
+	if out.UsedMemory, err = manager.GetUsedMemory(); err != nil {
+		return err
+	}
+	// older servers have no metadata
+	out.Metadata, _ = manager.GetMetadata()
+
+	return c.print(out, func(w io.Writer) {
+		fmt.Fprintf(w, "Version:\t%s\n", out.Version)
+		fmt.Fprintf(w, "Type:\t%s\n", out.Type)
+		fmt.Fprintf(w, "Capabilities:\t%s\n", strings.Join(out.Capabilities, ", "))
+		fmt.Fprintf(w, "CPUs:\t%d\n", out.CpuCount)
+		fmt.Fprintf(w, "CPU usage:\t%.1f%%\n", out.UsedCpu)
+		fmt.Fprintf(w, "Memory:\t%d KiB\n", out.UsedMemory)
+		if out.Metadata != "" {
+			fmt.Fprintf(w, "Metadata:\t%s\n", out.Metadata)
+		}
+	})
+}
+
+func (c *cli) modules(args []string) error {
+	fs := flag.NewFlagSet("modules", flag.ExitOnError)
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	info, err := c.conn.ServerManager().GetInfo()
+	if err != nil {
+		return err
+	}
+	modules := info.Modules
+	sort.Slice(modules, func(i, j int) bool { return modules[i].Name < modules[j].Name })
+	return c.print(modules, func(w io.Writer) {
+		fmt.Fprintln(w, "NAME\tVERSION\tGENERATED\tFACTORIES")
+		for _, m := range modules {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.Name, m.Version, m.GenerationTime, strings.Join(m.Factories, ","))
+		}
+	})
+}
+
+func (c *cli) listPipelines() ([]objectInfo, error) {
+	pipelines, err := c.conn.ServerManager().GetPipelines()
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]objectInfo, 0, len(pipelines))
+	for _, p := range pipelines {
+		info, err := c.describe(p.(kurento.IMediaObject).String(), false)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, info)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Created.Before(ret[j].Created) })
+	return ret, nil
+}
+
+func (c *cli) pipelines(args []string) error {
+	fs := flag.NewFlagSet("pipelines", flag.ExitOnError)
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	pipelines, err := c.listPipelines()
+	if err != nil {
+		return err
+	}
+	return c.print(pipelines, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tNAME\tAGE\tCHILDREN\tTAGS")
+		for _, p := range pipelines {
+			name := p.Name
+			if name == p.Id {
+				name = ""
+			}
+			age := time.Since(p.Created).Truncate(time.Second)
+			fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", p.Id, name, age, p.childCount, formatTags(p.Tags))
+		}
+	})
+}
+
+func (c *cli) sessions(args []string) error {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+
+	sessions, err := c.conn.ServerManager().GetSessions()
+	if err != nil {
+		return err
+	}
+	sort.Strings(sessions)
+	return c.print(sessions, func(w io.Writer) {
+		fmt.Fprintln(w, "SESSION")
+		for _, s := range sessions {
+			fmt.Fprintln(w, s)
+		}
+	})
+}
+
+func (c *cli) tree(args []string) error {
+	fs := flag.NewFlagSet("tree", flag.ExitOnError)
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	root, err := c.describe(args[0], true)
+	if err != nil {
+		return err
+	}
+	return c.print(root, func(w io.Writer) {
+		fmt.Fprintln(w, "OBJECT\tID\tNAME\tTAGS")
+		var walk func(info objectInfo, depth int)
+		walk = func(info objectInfo, depth int) {
+			name := info.Name
+			if name == info.Id {
+				name = ""
+			}
+			fmt.Fprintf(w, "%s%s\t%s\t%s\t%s\n", strings.Repeat("  ", depth), info.Type, shortId(info.Id), name, formatTags(info.Tags))
+			for _, child := range info.Children {
+				walk(child, depth+1)
+			}
+		}
+		walk(root, 0)
+	})
+}
+
+func (c *cli) dot(args []string) error {
+	fs := flag.NewFlagSet("dot", flag.ExitOnError)
+	details := fs.String("details", string(kurento.GSTREAMERDOTDETAILS_SHOW_ALL), "level of details, e.g. SHOW_MEDIA_TYPE or SHOW_VERBOSE")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	var dot string
+	level := kurento.GstreamerDotDetails(strings.ToUpper(*details))
+	if kurento.TypeFromId(args[0]) == "MediaPipeline" {
+		pipeline := &kurento.MediaPipeline{}
+		kurento.HydrateMediaObject(args[0], nil, c.conn, pipeline)
+		dot, err = pipeline.GetGstreamerDot(level)
+	} else {
+		elem := &kurento.MediaElement{}
+		kurento.HydrateMediaObject(args[0], nil, c.conn, elem)
+		dot, err = elem.GetGstreamerDot(level)
+	}
+	if err != nil {
+		return err
+	}
+	return c.print(map[string]string{"id": args[0], "dot": dot}, func(w io.Writer) {
+		fmt.Fprintln(w, dot)
+	})
+}
+
+func (c *cli) graph(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := fs.String("format", "dot", "output format: dot, mermaid or json")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	pipeline := &kurento.MediaPipeline{}
+	kurento.HydrateMediaObject(args[0], nil, c.conn, pipeline)
+	g, err := graph.Build(pipeline)
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "dot":
+		_, err = io.WriteString(c.out, g.DOT())
+	case "mermaid":
+		_, err = io.WriteString(c.out, g.Mermaid())
+	case "json":
+		enc := json.NewEncoder(c.out)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(g)
+	default:
+		err = fmt.Errorf("graph: unknown format %q", *format)
+	}
+	return err
+}
+
+func (c *cli) stats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	media := fs.String("media", "", "media type: audio, video or data; all if empty")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	elem := &kurento.MediaElement{}
+	kurento.HydrateMediaObject(args[0], nil, c.conn, elem)
+	report, err := elem.GetStatsReport(kurento.MediaType(strings.ToUpper(*media)))
+	if err != nil {
+		return err
+	}
+	return c.print(report, func(w io.Writer) {
+		ids := make([]string, 0, len(report))
+		for id := range report {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		fmt.Fprintln(w, "ID\tTYPE\tVALUES")
+		for _, id := range ids {
+			stats := report[id]
+			keys := make([]string, 0, len(stats))
+			for k := range stats {
+				if k != "id" && k != "type" {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			values := make([]string, len(keys))
+			for i, k := range keys {
+				v, _ := json.Marshal(stats[k])
+				values[i] = k + "=" + string(v)
+			}
+			fmt.Fprintf(w, "%s\t%v\t%s\n", id, stats["type"], strings.Join(values, " "))
+		}
+	})
+}
+
+func (c *cli) watch(args []string) error {
+	fs := flag.NewFlagSet("watch", flag.ExitOnError)
+	events := fs.String("events", strings.Join(defaultEvents, ","), "comma-separated events to watch")
+	args, err := parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	obj := c.object(args[0])
+	var lock sync.Mutex
+	enc := json.NewEncoder(c.out)
+	handlers := make(map[string]string)
+	for _, event := range strings.Split(*events, ",") {
+		event = strings.TrimSpace(event)
+		id, err := obj.Subscribe(event, func(data map[string]interface{}) {
+			lock.Lock()
+			defer lock.Unlock()
+
+			now := time.Now()
+			if c.json {
+				enc.Encode(map[string]interface{}{"time": now, "type": event, "object": args[0], "data": data})
+				return
+			}
+			raw, _ := json.Marshal(data)
+			fmt.Fprintf(c.out, "%s %s %s\n", now.Format("15:04:05.000"), event, raw)
+		})
+		if err != nil {
+			// the object does not raise this event
+			continue
+		}
+		handlers[event] = id
+	}
+	if len(handlers) == 0 {
+		return fmt.Errorf("watch: cannot subscribe to any event of %s", args[0])
+	}
+
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	select {
+	case <-interrupt:
+	case <-c.conn.Dead:
+		return errors.New("watch: connection lost")
+	}
+	for event, id := range handlers {
+		obj.Unsubscribe(event, id)
+	}
+	return nil
+}
+
+func (c *cli) release(args []string) error {
+	fs := flag.NewFlagSet("release", flag.ExitOnError)
+	args, err := parse(fs, args, -1)
+	if err != nil {
+		return err
+	}
+
+	var ret error
+	for _, id := range args {
+		if err := c.object(id).Release(); err != nil {
+			ret = errors.Join(ret, fmt.Errorf("%s: %w", id, err))
+		}
+	}
+	return ret
+}
+
+func (c *cli) releaseLeaked(args []string) error {
+	fs := flag.NewFlagSet("release-leaked", flag.ExitOnError)
+	olderThan := fs.Duration("older-than", 0, "release the pipelines created before this duration")
+	withoutTag := fs.String("without-tag", "", "release the pipelines without this tag")
+	empty := fs.Bool("empty", false, "release the pipelines without elements")
+	dryRun := fs.Bool("dry-run", false, "list the pipelines without releasing them")
+	if _, err := parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *olderThan == 0 && *withoutTag == "" && !*empty {
+		return errors.New("release-leaked: at least one of -older-than, -without-tag and -empty is required")
+	}
+
+	pipelines, err := c.listPipelines()
+	if err != nil {
+		return err
+	}
+	type result struct {
+		objectInfo
+		Released bool   `json:"released"`
+		Error    string `json:"error,omitempty"`
+	}
+	var results []result
+	for _, p := range pipelines {
+		if *olderThan > 0 && time.Since(p.Created) < *olderThan {
+			continue
+		}
+		if _, ok := p.Tags[*withoutTag]; *withoutTag != "" && ok {
+			continue
+		}
+		if *empty && p.childCount > 0 {
+			continue
+		}
+		r := result{objectInfo: p}
+		if !*dryRun {
+			if err := c.object(p.Id).Release(); err != nil {
+				r.Error = err.Error()
+			} else {
+				r.Released = true
+			}
+		}
+		results = append(results, r)
+	}
+	return c.print(results, func(w io.Writer) {
+		fmt.Fprintln(w, "ID\tAGE\tCHILDREN\tTAGS\tSTATUS")
+		for _, r := range results {
+			status := "released"
+			switch {
+			case *dryRun:
+				status = "would release"
+			case r.Error != "":
+				status = r.Error
+			}
+			age := time.Since(r.Created).Truncate(time.Second)
+			fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\n", r.Id, age, r.childCount, formatTags(r.Tags), status)
+		}
+	})
+}
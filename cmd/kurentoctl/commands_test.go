@@ -0,0 +1,172 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+func TestHelpers(t *testing.T) {
+	ids := []struct{ id, want string }{
+		{"0f6a3b3e_kurento.MediaPipeline", "0f6a3b3e"},
+		{"0f6a3b3e_kurento.MediaPipeline/5c0e1fa4_kurento.WebRtcEndpoint", "5c0e1fa4"},
+		{"plain", "plain"},
+	}
+	for _, tt := range ids {
+		if got := shortId(tt.id); got != tt.want {
+			t.Errorf("shortId(%s) = %s, want %s", tt.id, got, tt.want)
+		}
+	}
+	if got := formatTags(map[string]string{"room": "r1", "owner": "me"}); got != "owner=me,room=r1" {
+		t.Errorf("formatTags() = %s", got)
+	}
+
+	args := []struct {
+		args    []string
+		nargs   int
+		wantErr bool
+	}{
+		{nil, 0, false},
+		{[]string{"a"}, 0, true},
+		{[]string{"a"}, 1, false},
+		{nil, -1, true},
+		{[]string{"a", "b"}, -1, false},
+	}
+	for _, tt := range args {
+		fs := flag.NewFlagSet("cmd", flag.ContinueOnError)
+		if _, err := parse(fs, tt.args, tt.nargs); (err != nil) != tt.wantErr {
+			t.Errorf("parse(%q, %d) = %v", tt.args, tt.nargs, err)
+		}
+	}
+}
+
+const (
+	oldPipeline = "A_kurento.MediaPipeline"
+	newPipeline = "B_kurento.MediaPipeline"
+)
+
+// call is a request expected by a fake server, and its result.
+type call struct {
+	method, object, operation string
+	value                     interface{}
+}
+
+// serve starts a fake server answering calls in order, and returns a
+// connection to it.
+func serve(t *testing.T, calls []call) (*kurento.Connection, *replay.Server) {
+	t.Helper()
+
+	var entries []replay.Entry
+	add := func(dir string, message map[string]interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, replay.Entry{Dir: dir, Message: data})
+	}
+	for i, c := range calls {
+		add(replay.Sent, map[string]interface{}{
+			"method": c.method,
+			"params": map[string]interface{}{"object": c.object, "operation": c.operation},
+		})
+		add(replay.Received, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      i + 1,
+			"result":  map[string]interface{}{"value": c.value},
+		})
+	}
+	match := func(expected, got map[string]interface{}) bool {
+		e, _ := expected["params"].(map[string]interface{})
+		g, _ := got["params"].(map[string]interface{})
+		operation, _ := g["operation"].(string)
+		return expected["method"] == got["method"] && e["object"] == g["object"] && e["operation"] == operation
+	}
+
+	srv := replay.NewServer(entries, replay.ServerOptions{Match: match})
+	ts := httptest.NewServer(srv)
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		ts.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ts.Close()
+	})
+	return conn, srv
+}
+
+// describe expects the queries of the description of a pipeline.
+func describe(id string, age time.Duration, tags map[string]string, children []string) []call {
+	var tagList []map[string]string
+	for k, v := range tags {
+		tagList = append(tagList, map[string]string{"key": k, "value": v})
+	}
+	return []call{
+		{"invoke", id, "getName", id},
+		{"invoke", id, "getCreationTime", time.Now().Add(-age).Unix()},
+		{"invoke", id, "getTags", tagList},
+		{"invoke", id, "getChildren", children},
+	}
+}
+
+func TestReleaseLeaked(t *testing.T) {
+	list := append(append([]call{{"invoke", "manager_ServerManager", "getPipelines", []string{oldPipeline, newPipeline}}},
+		describe(oldPipeline, 2*time.Hour, map[string]string{"keep": "yes"}, nil)...),
+		describe(newPipeline, time.Minute, nil, []string{newPipeline + "/C_kurento.WebRtcEndpoint"})...)
+	release := func(id string) call { return call{"release", id, "", nil} }
+
+	tests := []struct {
+		name  string
+		args  []string
+		calls []call
+		want  string
+	}{
+		{"older than", []string{"-older-than", "1h"}, []call{release(oldPipeline)}, "[A_kurento.MediaPipeline:true]"},
+		{"without tag", []string{"-without-tag", "keep"}, []call{release(newPipeline)}, "[B_kurento.MediaPipeline:true]"},
+		{"empty", []string{"-empty"}, []call{release(oldPipeline)}, "[A_kurento.MediaPipeline:true]"},
+		{"all flags", []string{"-empty", "-older-than", "1h", "-without-tag", "keep"}, nil, "[]"},
+		{"dry run", []string{"-older-than", "1m", "-dry-run"}, nil, "[A_kurento.MediaPipeline:false B_kurento.MediaPipeline:false]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, srv := serve(t, append(append([]call(nil), list...), tt.calls...))
+			var out bytes.Buffer
+			c := &cli{conn: conn, json: true, out: &out}
+			if err := c.releaseLeaked(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := srv.Err(); err != nil {
+				t.Fatal(err)
+			}
+
+			var results []struct {
+				Id       string
+				Released bool
+			}
+			if err := json.Unmarshal(out.Bytes(), &results); err != nil {
+				t.Fatalf("%v: %s", err, out.String())
+			}
+			got := make([]string, 0, len(results))
+			for _, r := range results {
+				got = append(got, fmt.Sprintf("%s:%v", r.Id, r.Released))
+			}
+			if fmt.Sprint(got) != tt.want {
+				t.Errorf("results %v, want %s", got, tt.want)
+			}
+		})
+	}
+
+	c := &cli{}
+	if err := c.releaseLeaked(nil); err == nil {
+		t.Error("release-leaked without flags")
+	}
+}
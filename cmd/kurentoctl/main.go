@@ -0,0 +1,120 @@
+// Command kurentoctl inspects and manages a running Kurento Media Server.
+//
+// Usage:
+//
+//	kurentoctl [-url ws://localhost:8888] [-o table|json] command [args]
+//
+// Commands:
+//
+//	info                         server version, capabilities and load
+//	modules                      loaded modules and their factories
+//	pipelines                    pipelines with their age and tags
+//	sessions                     sessions of the server
+//	tree <id>                    objects under a pipeline or an element, with tags
+//	dot [-details d] <id>        GStreamer graph of a pipeline or an element
+//	graph [-format f] <id>       graph of the elements of a pipeline, as dot, mermaid or json
+//	stats [-media m] <id>        stats of an element
+//	watch [-events e,...] <id>   events raised by an object, until interrupted
+//	release <id> ...             release objects
+//	release-leaked [flags]       release the pipelines matching all the flags
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+type command struct {
+	usage string
+	run   func(c *cli, args []string) error
+}
+
+var commands = map[string]command{
+	"info":           {"info", (*cli).info},
+	"modules":        {"modules", (*cli).modules},
+	"pipelines":      {"pipelines", (*cli).pipelines},
+	"sessions":       {"sessions", (*cli).sessions},
+	"tree":           {"tree <id>", (*cli).tree},
+	"dot":            {"dot [-details d] <id>", (*cli).dot},
+	"graph":          {"graph [-format dot|mermaid|json] <id>", (*cli).graph},
+	"stats":          {"stats [-media m] <id>", (*cli).stats},
+	"watch":          {"watch [-events e,...] <id>", (*cli).watch},
+	"release":        {"release <id> ...", (*cli).release},
+	"release-leaked": {"release-leaked [-older-than d] [-without-tag key] [-empty] [-dry-run]", (*cli).releaseLeaked},
+}
+
+func main() {
+	url := flag.String("url", "ws://localhost:8888", "URL of the media server")
+	output := flag.String("o", "table", "output format: table or json")
+	timeout := flag.Duration("timeout", 10*time.Second, "timeout of the connection")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "kurentoctl: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if *output != "table" && *output != "json" {
+		fmt.Fprintf(os.Stderr, "kurentoctl: unknown output format %q\n", *output)
+		os.Exit(2)
+	}
+
+	conn, err := connect(*url, *timeout)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "kurentoctl:", err)
+		os.Exit(1)
+	}
+	defer conn.Close()
+
+	c := &cli{conn: conn, json: *output == "json", out: os.Stdout}
+	if err := cmd.run(c, flag.Args()[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "kurentoctl:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [flags] command [args]\n\nCommands:\n", os.Args[0])
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "  %s\n", commands[name].usage)
+	}
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
+}
+
+// connect opens a connection, giving up after timeout.
+func connect(url string, timeout time.Duration) (*kurento.Connection, error) {
+	type result struct {
+		conn *kurento.Connection
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := kurento.NewConnection(strings.TrimSuffix(url, "/kurento"))
+		done <- result{conn, err}
+	}()
+	select {
+	case r := <-done:
+		return r.conn, r.err
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("cannot connect to %s after %s", url, timeout)
+	}
+}
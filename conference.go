@@ -0,0 +1,425 @@
+package kurento
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ConferenceLayout is the way a Conference mixes the video of its
+// participants.
+type ConferenceLayout int
+
+const (
+	// LayoutGrid shows every participant in a grid, using a Composite.
+	LayoutGrid ConferenceLayout = iota
+
+	// LayoutPresenter shows one participant full size, the others being
+	// overlaid as thumbnails, using an AlphaBlending.
+	LayoutPresenter
+)
+
+// PortProperties place the video of a port of an AlphaBlending, relative to
+// the size of the output.
+type PortProperties struct {
+	RelativeX      float64
+	RelativeY      float64
+	RelativeWidth  float64
+	RelativeHeight float64
+	ZOrder         int
+}
+
+// DefaultThumbnail places the thumbnails of the presenter layout in rows of
+// five, from the bottom left corner upwards. Beyond 25 thumbnails, the grid
+// grows to n rows of n smaller thumbnails, so that they all fit.
+func DefaultThumbnail(index, count int) PortProperties {
+	if count <= index {
+		count = index + 1
+	}
+	n := 5
+	for n*n < count {
+		n++
+	}
+	size := 1 / float64(n)
+	return PortProperties{
+		RelativeX:      float64(index%n) * size,
+		RelativeY:      1 - float64(index/n+1)*size,
+		RelativeWidth:  size,
+		RelativeHeight: size,
+		ZOrder:         index + 1,
+	}
+}
+
+// ConferenceOptions configure a Conference.
+type ConferenceOptions struct {
+	// MaxParticipants is the maximum number of participants, 0 for no limit.
+	MaxParticipants int
+
+	// OnIceCandidate sends a local candidate of the endpoint of participant
+	// id to that participant. Required.
+	OnIceCandidate func(id string, candidate IceCandidate) error
+
+	// OnIceGatheringDone tells participant id that all its candidates were
+	// sent. Optional.
+	OnIceGatheringDone func(id string) error
+
+	// Thumbnail places the index-th of count thumbnails in the presenter
+	// layout. DefaultThumbnail if nil.
+	Thumbnail func(index, count int) PortProperties
+}
+
+// conferencePeer is a participant of a Conference. Its endpoint sends its
+// media to a port of each hub, and receives the mix of the hub of the
+// current layout.
+type conferencePeer struct {
+	id          string
+	endpoint    *WebRtcEndpoint
+	trickle     *TrickleSession
+	gridPort    *HubPort
+	overlayPort *HubPort
+}
+
+func (p *conferencePeer) release() error {
+	var ret error
+	p.trickle.Close()
+	for _, port := range []*HubPort{p.gridPort, p.overlayPort} {
+		if port == nil {
+			continue
+		}
+		if err := port.Release(); err != nil {
+			ret = err
+		}
+	}
+	if err := p.endpoint.Release(); err != nil {
+		ret = err
+	}
+	return ret
+}
+
+// Conference mixes the media of many WebRTC participants, every participant
+// receiving the mix. The layout can be switched at any time; participants
+// keep their endpoints and only the mix they receive changes.
+//
+// The AlphaBlending of the presenter layout is created the first time that
+// layout is used, and then kept along the Composite.
+type Conference struct {
+	Pipeline      *MediaPipeline
+	Composite     *Composite
+	AlphaBlending *AlphaBlending
+
+	options      ConferenceOptions
+	lock         sync.Mutex
+	participants map[string]*conferencePeer
+	order        []string
+	layout       ConferenceLayout
+	presenter    string
+	released     bool
+}
+
+// NewConference creates the pipeline of a conference on c, in the grid
+// layout.
+func NewConference(c *Connection, options ConferenceOptions) (*Conference, error) {
+	if options.OnIceCandidate == nil {
+		return nil, errMissingOnIce
+	}
+	if options.Thumbnail == nil {
+		options.Thumbnail = DefaultThumbnail
+	}
+	conf := &Conference{
+		Pipeline:     &MediaPipeline{},
+		Composite:    &Composite{},
+		options:      options,
+		participants: make(map[string]*conferencePeer),
+	}
+	if err := c.Create(conf.Pipeline, nil); err != nil {
+		return nil, err
+	}
+	if err := conf.Pipeline.Create(conf.Composite, nil); err != nil {
+		conf.Pipeline.Release()
+		return nil, err
+	}
+	return conf, nil
+}
+
+// Join negotiates a participant from its offer, gives the answer to
+// sendAnswer and adds it to the mix. The participant is added before its
+// offer is accepted, which is done without the lock held, so its candidates
+// are buffered until then.
+func (conf *Conference) Join(id, offer string, sendAnswer func(answer string) error) error {
+	p, err := conf.join(id)
+	if err != nil {
+		return err
+	}
+	if err := p.trickle.AcceptOffer(offer, sendAnswer); err != nil {
+		// unless it left meanwhile
+		if lerr := conf.leave(p); lerr != nil && !errors.Is(lerr, ErrPeerNotFound) {
+			return errors.Join(err, lerr)
+		}
+		return err
+	}
+	return nil
+}
+
+// join adds participant id to the mix, before its negotiation.
+func (conf *Conference) join(id string) (*conferencePeer, error) {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	switch {
+	case conf.released:
+		return nil, ErrRoomReleased
+	case conf.participants[id] != nil:
+		return nil, ErrPeerExists
+	case conf.options.MaxParticipants > 0 && len(conf.participants) >= conf.options.MaxParticipants:
+		return nil, ErrRoomFull
+	}
+
+	endpoint, trickle, err := newTricklePeer(conf.Pipeline, id, WebRtcEndpointOptions{},
+		conf.options.OnIceCandidate, conf.options.OnIceGatheringDone)
+	if err != nil {
+		return nil, err
+	}
+	p := &conferencePeer{id: id, endpoint: endpoint, trickle: trickle}
+
+	p.gridPort, err = conf.addPort(p, conf.Composite)
+	if err == nil && conf.AlphaBlending != nil {
+		p.overlayPort, err = conf.addPort(p, conf.AlphaBlending)
+	}
+	if err == nil {
+		err = conf.activePort(p).Connect(p.endpoint, "", "", "")
+	}
+	if err != nil {
+		p.release()
+		return nil, err
+	}
+
+	conf.participants[id] = p
+	conf.order = append(conf.order, id)
+	if conf.layout == LayoutPresenter {
+		if err := conf.placeThumbnails(); err != nil {
+			conf.remove(id)
+			p.release()
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// addPort creates a port of hub and connects the endpoint of p to it.
+func (conf *Conference) addPort(p *conferencePeer, hub IMediaObject) (*HubPort, error) {
+	port := &HubPort{}
+	if err := hub.Create(port, nil); err != nil {
+		return nil, err
+	}
+	if err := p.endpoint.Connect(port, "", "", ""); err != nil {
+		port.Release()
+		return nil, err
+	}
+	return port, nil
+}
+
+// activePort is the port p receives the mix from in the current layout.
+func (conf *Conference) activePort(p *conferencePeer) *HubPort {
+	if conf.layout == LayoutPresenter {
+		return p.overlayPort
+	}
+	return p.gridPort
+}
+
+// Leave releases a participant. If it was the presenter, the conference goes
+// back to the grid layout.
+func (conf *Conference) Leave(id string) error {
+	conf.lock.Lock()
+	p, ok := conf.participants[id]
+	conf.lock.Unlock()
+
+	if !ok {
+		return ErrPeerNotFound
+	}
+	return conf.leave(p)
+}
+
+// leave releases p if it is still a participant.
+func (conf *Conference) leave(p *conferencePeer) error {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	if conf.participants[p.id] != p {
+		return ErrPeerNotFound
+	}
+	conf.remove(p.id)
+
+	var err error
+	if conf.layout == LayoutPresenter {
+		if p.id == conf.presenter {
+			err = conf.setGridLayout()
+		} else {
+			err = conf.placeThumbnails()
+		}
+	}
+	if rerr := p.release(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// remove removes participant id from the mix. It must be called with the
+// lock held.
+func (conf *Conference) remove(id string) {
+	delete(conf.participants, id)
+	for i, other := range conf.order {
+		if other == id {
+			conf.order = append(conf.order[:i], conf.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// SetGridLayout switches to the grid layout.
+func (conf *Conference) SetGridLayout() error {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	if conf.released {
+		return ErrRoomReleased
+	}
+	if conf.layout == LayoutGrid {
+		return nil
+	}
+	return conf.setGridLayout()
+}
+
+// setGridLayout must be called with the lock held.
+func (conf *Conference) setGridLayout() error {
+	conf.layout = LayoutGrid
+	conf.presenter = ""
+	for _, id := range conf.order {
+		p := conf.participants[id]
+		if err := p.gridPort.Connect(p.endpoint, "", "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetPresenterLayout switches to the presenter layout, participant id being
+// shown full size. It can be called again to change the presenter.
+func (conf *Conference) SetPresenterLayout(id string) error {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	if conf.released {
+		return ErrRoomReleased
+	}
+	presenter, ok := conf.participants[id]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+
+	if conf.AlphaBlending == nil {
+		hub := &AlphaBlending{}
+		if err := conf.Pipeline.Create(hub, nil); err != nil {
+			return err
+		}
+		conf.AlphaBlending = hub
+	}
+	for _, pid := range conf.order {
+		p := conf.participants[pid]
+		if p.overlayPort != nil {
+			continue
+		}
+		port, err := conf.addPort(p, conf.AlphaBlending)
+		if err != nil {
+			return err
+		}
+		p.overlayPort = port
+	}
+
+	if err := conf.AlphaBlending.SetMaster(presenter.overlayPort, 0); err != nil {
+		return err
+	}
+	wasGrid := conf.layout == LayoutGrid
+	conf.layout = LayoutPresenter
+	conf.presenter = id
+	if err := conf.placeThumbnails(); err != nil {
+		return err
+	}
+	if !wasGrid {
+		return nil
+	}
+	for _, pid := range conf.order {
+		p := conf.participants[pid]
+		if err := p.overlayPort.Connect(p.endpoint, "", "", ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeThumbnails places every participant but the presenter, in join
+// order. It must be called with the lock held.
+func (conf *Conference) placeThumbnails() error {
+	count := len(conf.order) - 1
+	index := 0
+	for _, id := range conf.order {
+		if id == conf.presenter {
+			continue
+		}
+		pp := conf.options.Thumbnail(index, count)
+		port := conf.participants[id].overlayPort
+		err := conf.AlphaBlending.SetPortProperties(pp.RelativeX, pp.RelativeY, pp.ZOrder,
+			pp.RelativeWidth, pp.RelativeHeight, port)
+		if err != nil {
+			return err
+		}
+		index++
+	}
+	return nil
+}
+
+// Layout returns the current layout, and the presenter in the presenter
+// layout.
+func (conf *Conference) Layout() (layout ConferenceLayout, presenter string) {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	return conf.layout, conf.presenter
+}
+
+// AddIceCandidate gives a candidate received from participant id to its
+// endpoint.
+func (conf *Conference) AddIceCandidate(id string, candidate IceCandidate) error {
+	conf.lock.Lock()
+	p := conf.participants[id]
+	conf.lock.Unlock()
+
+	if p == nil {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	return p.trickle.AddRemoteCandidate(candidate)
+}
+
+// Participants returns the IDs of the participants, in join order.
+func (conf *Conference) Participants() []string {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	return append([]string(nil), conf.order...)
+}
+
+// Release releases the pipeline, and every participant with it.
+func (conf *Conference) Release() error {
+	conf.lock.Lock()
+	defer conf.lock.Unlock()
+
+	if conf.released {
+		return nil
+	}
+	conf.released = true
+	for _, p := range conf.participants {
+		p.trickle.Close()
+	}
+	conf.participants = nil
+	conf.order = nil
+	return conf.Pipeline.Release()
+}
@@ -0,0 +1,220 @@
+package kurento_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// conferencePeer expects the creation of the endpoint of a participant of the
+// conference "conf", and of its ports: always on the grid, on the overlay if
+// the AlphaBlending exists. The endpoint receives the mix of its port on
+// active.
+func (s *script) conferencePeer(id string, overlay bool, active string) {
+	s.call("create", map[string]interface{}{
+		"type":              "WebRtcEndpoint",
+		"constructorParams": map[string]interface{}{"mediaPipeline": "conf"},
+	}, id)
+	s.subscribe(id, "IceCandidateFound", id+"/found")
+	s.subscribe(id, "IceGatheringDone", id+"/done")
+	s.conferencePort(id, "grid")
+	if overlay {
+		s.conferencePort(id, "overlay")
+	}
+	s.connect(id+"/"+active, id)
+}
+
+// conferencePort expects the creation of the port of participant id on hub,
+// and its connection from the endpoint.
+func (s *script) conferencePort(id, hub string) {
+	s.call("create", map[string]interface{}{
+		"type":              "HubPort",
+		"constructorParams": map[string]interface{}{"hub": "conf/" + hub},
+	}, id+"/"+hub)
+	s.connect(id, id+"/"+hub)
+}
+
+// negotiate expects the negotiation of the endpoint of participant id.
+func (s *script) negotiate(id string) {
+	s.invoke(id, "processOffer", "answer "+id)
+	s.invoke(id, "gatherCandidates", true)
+}
+
+func (s *script) setMaster(id string) {
+	s.call("invoke", map[string]interface{}{
+		"object":          "conf/overlay",
+		"operation":       "setMaster",
+		"operationParams": map[string]interface{}{"source": id + "/overlay", "zOrder": 0},
+	}, nil)
+}
+
+// thumbnail expects the placement of the zOrder-th thumbnail.
+func (s *script) thumbnail(id string, zOrder int) {
+	s.call("invoke", map[string]interface{}{
+		"object":          "conf/overlay",
+		"operation":       "setPortProperties",
+		"operationParams": map[string]interface{}{"port": id + "/overlay", "zOrder": zOrder},
+	}, nil)
+}
+
+// releaseConferencePeer expects the release of a participant and its ports.
+func (s *script) releaseConferencePeer(id string, overlay bool) {
+	s.unsubscribe(id)
+	s.unsubscribe(id)
+	s.release(id + "/grid")
+	if overlay {
+		s.release(id + "/overlay")
+	}
+	s.release(id)
+}
+
+func TestConference(t *testing.T) {
+	s := &script{}
+	s.create("MediaPipeline", "conf")
+	s.create("Composite", "conf/grid")
+	s.conferencePeer("conf/alice", false, "grid")
+	s.negotiate("conf/alice")
+	s.conferencePeer("conf/bob", false, "grid")
+	s.negotiate("conf/bob")
+	// the presenter layout, whose hub and ports are created the first time
+	s.create("AlphaBlending", "conf/overlay")
+	s.conferencePort("conf/alice", "overlay")
+	s.conferencePort("conf/bob", "overlay")
+	s.setMaster("conf/alice")
+	s.thumbnail("conf/bob", 1)
+	s.connect("conf/alice/overlay", "conf/alice")
+	s.connect("conf/bob/overlay", "conf/bob")
+	// a participant joining the presenter layout
+	s.conferencePeer("conf/carol", true, "overlay")
+	s.thumbnail("conf/bob", 1)
+	s.thumbnail("conf/carol", 2)
+	s.negotiate("conf/carol")
+	// a thumbnail leaves
+	s.thumbnail("conf/carol", 1)
+	s.releaseConferencePeer("conf/bob", true)
+	// a new presenter, in the same layout
+	s.setMaster("conf/carol")
+	s.thumbnail("conf/alice", 1)
+	// the presenter leaves, back to the grid
+	s.connect("conf/alice/grid", "conf/alice")
+	s.releaseConferencePeer("conf/carol", true)
+	// the presenter layout again, then the grid
+	s.setMaster("conf/alice")
+	s.connect("conf/alice/overlay", "conf/alice")
+	s.connect("conf/alice/grid", "conf/alice")
+	// a participant whose offer fails
+	s.conferencePeer("conf/dave", true, "grid")
+	s.fail("invoke", map[string]interface{}{"object": "conf/dave", "operation": "processOffer"}, 40208, "bad offer")
+	s.releaseConferencePeer("conf/dave", true)
+	s.unsubscribe("conf/alice")
+	s.unsubscribe("conf/alice")
+	s.release("conf")
+	conn, srv := s.serve(t)
+
+	if _, err := kurento.NewConference(conn, kurento.ConferenceOptions{}); err == nil {
+		t.Fatal("conference without OnIceCandidate")
+	}
+	var conf *kurento.Conference
+	conf, err := kurento.NewConference(conn, kurento.ConferenceOptions{
+		MaxParticipants: 3,
+		OnIceCandidate:  func(string, kurento.IceCandidate) error { return nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// errKMS stands for any error of KMS
+	errKMS := errors.New("KMS error")
+	answer := func(string) error { return nil }
+	// the participant is added before its offer is accepted
+	carolAnswer := func(string) error {
+		if fmt.Sprint(conf.Participants()) != "[conf/alice conf/bob conf/carol]" {
+			t.Errorf("participants when answering: %v", conf.Participants())
+		}
+		return nil
+	}
+	layout := func(want string) func() error {
+		return func() error {
+			if l, presenter := conf.Layout(); fmt.Sprintf("%d %s", l, presenter) != want {
+				return fmt.Errorf("layout %d %q, want %s", l, presenter, want)
+			}
+			return nil
+		}
+	}
+	steps := []struct {
+		name   string
+		action func() error
+		want   error
+	}{
+		{"join", func() error { return conf.Join("conf/alice", "offer", answer) }, nil},
+		{"join again", func() error { return conf.Join("conf/alice", "offer", answer) }, kurento.ErrPeerExists},
+		{"second join", func() error { return conf.Join("conf/bob", "offer", answer) }, nil},
+		{"unknown presenter", func() error { return conf.SetPresenterLayout("conf/dave") }, kurento.ErrPeerNotFound},
+		{"presenter layout", func() error { return conf.SetPresenterLayout("conf/alice") }, nil},
+		{"presenter", layout("1 conf/alice"), nil},
+		{"join presenter layout", func() error { return conf.Join("conf/carol", "offer", carolAnswer) }, nil},
+		{"full", func() error { return conf.Join("conf/dave", "offer", answer) }, kurento.ErrRoomFull},
+		{"thumbnail leaves", func() error { return conf.Leave("conf/bob") }, nil},
+		{"new presenter", func() error { return conf.SetPresenterLayout("conf/carol") }, nil},
+		{"presenter leaves", func() error { return conf.Leave("conf/carol") }, nil},
+		{"grid", layout("0 "), nil},
+		{"grid again", conf.SetGridLayout, nil},
+		{"presenter layout again", func() error { return conf.SetPresenterLayout("conf/alice") }, nil},
+		{"grid layout", conf.SetGridLayout, nil},
+		{"offer fails", func() error { return conf.Join("conf/dave", "offer", answer) }, errKMS},
+		{"participant left", func() error { return conf.Leave("conf/dave") }, kurento.ErrPeerNotFound},
+		{"release", conf.Release, nil},
+		{"join after release", func() error { return conf.Join("conf/alice", "offer", answer) }, kurento.ErrRoomReleased},
+	}
+	for _, step := range steps {
+		err := step.action()
+		if step.want == errKMS {
+			if err == nil {
+				t.Fatalf("%s: no error", step.name)
+			}
+			continue
+		}
+		if !errors.Is(err, step.want) {
+			t.Fatalf("%s: err = %v, want %v", step.name, err, step.want)
+		}
+	}
+	done(t, srv)
+}
+
+func TestDefaultThumbnail(t *testing.T) {
+	tests := []struct {
+		index, count int
+		want         kurento.PortProperties
+	}{
+		{0, 1, kurento.PortProperties{RelativeX: 0, RelativeY: 0.8, RelativeWidth: 0.2, RelativeHeight: 0.2, ZOrder: 1}},
+		{6, 10, kurento.PortProperties{RelativeX: 0.2, RelativeY: 0.6, RelativeWidth: 0.2, RelativeHeight: 0.2, ZOrder: 7}},
+		{24, 25, kurento.PortProperties{RelativeX: 0.8, RelativeY: 0, RelativeWidth: 0.2, RelativeHeight: 0.2, ZOrder: 25}},
+		// the grid grows to 6 by 6
+		{0, 26, kurento.PortProperties{RelativeX: 0, RelativeY: 5.0 / 6, RelativeWidth: 1.0 / 6, RelativeHeight: 1.0 / 6, ZOrder: 1}},
+		{35, 36, kurento.PortProperties{RelativeX: 5.0 / 6, RelativeY: 0, RelativeWidth: 1.0 / 6, RelativeHeight: 1.0 / 6, ZOrder: 36}},
+		// a count too low is ignored
+		{30, 0, kurento.PortProperties{RelativeX: 0, RelativeY: 0, RelativeWidth: 1.0 / 6, RelativeHeight: 1.0 / 6, ZOrder: 31}},
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	for _, tt := range tests {
+		got := kurento.DefaultThumbnail(tt.index, tt.count)
+		if !near(got.RelativeX, tt.want.RelativeX) || !near(got.RelativeY, tt.want.RelativeY) ||
+			!near(got.RelativeWidth, tt.want.RelativeWidth) || !near(got.RelativeHeight, tt.want.RelativeHeight) ||
+			got.ZOrder != tt.want.ZOrder {
+			t.Errorf("DefaultThumbnail(%d, %d) = %+v, want %+v", tt.index, tt.count, got, tt.want)
+		}
+	}
+
+	// every thumbnail is within the output
+	for count := 1; count <= 100; count++ {
+		for index := range count {
+			p := kurento.DefaultThumbnail(index, count)
+			if p.RelativeX < -1e-9 || p.RelativeY < -1e-9 ||
+				p.RelativeX+p.RelativeWidth > 1+1e-9 || p.RelativeY+p.RelativeHeight > 1+1e-9 {
+				t.Fatalf("DefaultThumbnail(%d, %d) = %+v is outside", index, count, p)
+			}
+		}
+	}
+}
@@ -0,0 +1,397 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrDataChannelPending is returned by Create while a channel of the same
+// label and protocol is being created, as the two could not be told apart
+// when they open.
+var ErrDataChannelPending = errors.New("kurento: a data channel of this label is being created")
+
+const (
+	// dataChannelStatsRetry is the interval at which the stats of an
+	// endpoint are read again while an open channel is not in them.
+	dataChannelStatsRetry = 100 * time.Millisecond
+	// dataChannelStatsTimeout is the time after which an open channel
+	// missing from the stats is taken as opened by the peer.
+	dataChannelStatsTimeout = 2 * time.Second
+	// defaultDataChannelOpenTimeout is the default of
+	// DataChannels.OpenTimeout.
+	defaultDataChannelOpenTimeout = 30 * time.Second
+)
+
+// DataChannelOptions are the parameters of a data channel created by KMS.
+type DataChannelOptions struct {
+	Label string
+	// Ordered delivery of the messages, false for unordered
+	Ordered bool
+	// Maximum time in ms during which a message is retransmitted, nil for
+	// no limit. Exclusive with MaxRetransmits.
+	MaxPacketLifeTime *int
+	// Maximum number of retransmissions of a message, nil for no limit. 0
+	// sends every message once.
+	MaxRetransmits *int
+	// Name of the sub-protocol used
+	Protocol string
+}
+
+// unlimited returns the value of an optional limit of DataChannelOptions,
+// -1 if it is not set.
+func unlimited(limit *int) int {
+	if limit == nil {
+		return -1
+	}
+	return *limit
+}
+
+// DataChannel is a data channel of a WebRtcEndpoint. Its ID is known once it
+// is open.
+type DataChannel struct {
+	Endpoint *WebRtcEndpoint
+	Label    string
+	Protocol string
+
+	lock   sync.Mutex
+	id     int
+	opened chan struct{}
+	closed chan struct{}
+	// expiry fails the channel if it is still pending after the open
+	// timeout
+	expiry *time.Timer
+}
+
+func newDataChannel(ep *WebRtcEndpoint, label string) *DataChannel {
+	return &DataChannel{
+		Endpoint: ep,
+		Label:    label,
+		id:       -1,
+		opened:   make(chan struct{}),
+		closed:   make(chan struct{}),
+	}
+}
+
+// ID returns the channel ID, and false until the channel is open.
+func (dc *DataChannel) ID() (int, bool) {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	return dc.id, dc.id >= 0
+}
+
+// Opened is closed once the channel is open.
+func (dc *DataChannel) Opened() <-chan struct{} {
+	return dc.opened
+}
+
+// Closed is closed once the channel is closed.
+func (dc *DataChannel) Closed() <-chan struct{} {
+	return dc.closed
+}
+
+// WaitOpen waits until the channel is open and returns its ID.
+func (dc *DataChannel) WaitOpen(ctx context.Context) (int, error) {
+	select {
+	case <-dc.opened:
+		id, _ := dc.ID()
+		return id, nil
+	case <-dc.closed:
+		return -1, errors.New("kurento: data channel closed before opening")
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	}
+}
+
+// Stats returns the stats of the channel.
+func (dc *DataChannel) Stats() (RTCDataChannelStats, error) {
+	id, ok := dc.ID()
+	if !ok {
+		return RTCDataChannelStats{}, errors.New("kurento: data channel is not open")
+	}
+	report, err := dc.Endpoint.GetStatsReport("")
+	if err != nil {
+		return RTCDataChannelStats{}, err
+	}
+	for _, stats := range report.DataChannels() {
+		if stats.Datachannelid == int64(id) {
+			return stats, nil
+		}
+	}
+	return RTCDataChannelStats{}, fmt.Errorf("kurento: no stats for data channel %d", id)
+}
+
+// Close closes the channel.
+func (dc *DataChannel) Close() error {
+	id, ok := dc.ID()
+	if !ok {
+		return errors.New("kurento: data channel is not open")
+	}
+	return dc.Endpoint.CloseDataChannel(id)
+}
+
+// DataChannels tracks the data channels of a WebRtcEndpoint created with
+// UseDataChannels, from its DataChannelOpen and DataChannelClose events,
+// which are handled in order.
+//
+// KMS does not return the ID of a channel it creates: when a channel opens,
+// its label and protocol are looked up in the stats of the endpoint and the
+// channel is given to the pending Create with the same ones. Only one
+// channel of a given label and protocol can be pending at a time.
+type DataChannels struct {
+	Endpoint *WebRtcEndpoint
+
+	// OnRemoteOpen is called with the channels opened by the peer, and by
+	// KMS when their Create cannot be found. It is called from the
+	// goroutine handling the events. Optional.
+	OnRemoteOpen func(*DataChannel)
+
+	// OpenTimeout is the time after which a channel created and not open
+	// is closed, and its label can be created again. 30s if zero.
+	OpenTimeout time.Duration
+
+	lock     sync.Mutex
+	pending  []*DataChannel
+	open     map[int]*DataChannel
+	handlers map[string]string
+
+	// events waiting to be handled, in order
+	events []dataChannelEvent
+	wake   chan struct{}
+	stop   chan struct{}
+	once   sync.Once
+}
+
+type dataChannelEvent struct {
+	id   int
+	open bool
+}
+
+// NewDataChannels starts tracking the data channels of ep.
+func NewDataChannels(ep *WebRtcEndpoint) (*DataChannels, error) {
+	d := &DataChannels{
+		Endpoint: ep,
+		open:     make(map[int]*DataChannel),
+		handlers: make(map[string]string),
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+	}
+	// the label is read from the stats, which cannot be requested from the
+	// event loop
+	go d.run()
+	id, err := ep.SubscribeDataChannelOpen(func(ev DataChannelOpenEvent) {
+		d.enqueue(dataChannelEvent{id: ev.ChannelId, open: true})
+	})
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	d.handlers["DataChannelOpen"] = id
+	id, err = ep.SubscribeDataChannelClose(func(ev DataChannelCloseEvent) {
+		d.enqueue(dataChannelEvent{id: ev.ChannelId})
+	})
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	d.handlers["DataChannelClose"] = id
+	return d, nil
+}
+
+// Create asks KMS to create a data channel. The returned channel is open
+// once the peer accepts it. It fails with ErrDataChannelPending if a channel
+// of the same label and protocol is not open yet.
+func (d *DataChannels) Create(options DataChannelOptions) (*DataChannel, error) {
+	dc := newDataChannel(d.Endpoint, options.Label)
+	dc.Protocol = options.Protocol
+	d.lock.Lock()
+	for _, p := range d.pending {
+		if p.Label == dc.Label && p.Protocol == dc.Protocol {
+			d.lock.Unlock()
+			return nil, fmt.Errorf("%w: %q", ErrDataChannelPending, dc.Label)
+		}
+	}
+	d.pending = append(d.pending, dc)
+	d.lock.Unlock()
+
+	err := d.Endpoint.CreateDataChannel(options.Label, options.Ordered,
+		unlimited(options.MaxPacketLifeTime), unlimited(options.MaxRetransmits), options.Protocol)
+	if err != nil {
+		d.lock.Lock()
+		d.removePending(dc)
+		d.lock.Unlock()
+		return nil, err
+	}
+
+	timeout := d.OpenTimeout
+	if timeout <= 0 {
+		timeout = defaultDataChannelOpenTimeout
+	}
+	d.lock.Lock()
+	dc.expiry = time.AfterFunc(timeout, func() {
+		d.lock.Lock()
+		expired := d.removePending(dc)
+		d.lock.Unlock()
+		if expired {
+			close(dc.closed)
+		}
+	})
+	d.lock.Unlock()
+	return dc, nil
+}
+
+// removePending removes dc from the pending channels, and tells if it was
+// pending. It must be called with the lock held.
+func (d *DataChannels) removePending(dc *DataChannel) bool {
+	for i, p := range d.pending {
+		if p == dc {
+			d.pending = append(d.pending[:i], d.pending[i+1:]...)
+			if dc.expiry != nil {
+				dc.expiry.Stop()
+			}
+			return true
+		}
+	}
+	return false
+}
+
+// enqueue adds an event to handle. It is called from the event loop.
+func (d *DataChannels) enqueue(ev dataChannelEvent) {
+	d.lock.Lock()
+	d.events = append(d.events, ev)
+	d.lock.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run handles the events in order, until Close.
+func (d *DataChannels) run() {
+	for {
+		select {
+		case <-d.wake:
+		case <-d.stop:
+			return
+		}
+		for {
+			d.lock.Lock()
+			if len(d.events) == 0 {
+				d.lock.Unlock()
+				break
+			}
+			ev := d.events[0]
+			d.events = d.events[1:]
+			d.lock.Unlock()
+
+			if ev.open {
+				d.opened(ev.id)
+			} else {
+				d.closed(ev.id)
+			}
+		}
+	}
+}
+
+// lookup returns the label and protocol of channel id from the stats of the
+// endpoint, which may not list a channel which just opened.
+func (d *DataChannels) lookup(id int) (label, protocol string, found bool) {
+	deadline := time.Now().Add(dataChannelStatsTimeout)
+	for {
+		if report, err := d.Endpoint.GetStatsReport(""); err == nil {
+			for _, stats := range report.DataChannels() {
+				if stats.Datachannelid == int64(id) {
+					return stats.Label, stats.Protocol, true
+				}
+			}
+		}
+		if time.Now().Add(dataChannelStatsRetry).After(deadline) {
+			return "", "", false
+		}
+		select {
+		case <-time.After(dataChannelStatsRetry):
+		case <-d.stop:
+			return "", "", false
+		}
+	}
+}
+
+func (d *DataChannels) opened(id int) {
+	label, protocol, found := d.lookup(id)
+
+	d.lock.Lock()
+	var dc *DataChannel
+	for _, p := range d.pending {
+		if found && p.Label == label && p.Protocol == protocol {
+			dc = p
+			break
+		}
+	}
+	if dc != nil {
+		d.removePending(dc)
+	}
+	remote := dc == nil
+	if remote {
+		dc = newDataChannel(d.Endpoint, label)
+		dc.Protocol = protocol
+	}
+	d.open[id] = dc
+	d.lock.Unlock()
+
+	dc.lock.Lock()
+	dc.id = id
+	dc.lock.Unlock()
+	close(dc.opened)
+
+	if remote && d.OnRemoteOpen != nil {
+		d.OnRemoteOpen(dc)
+	}
+}
+
+func (d *DataChannels) closed(id int) {
+	d.lock.Lock()
+	dc, ok := d.open[id]
+	delete(d.open, id)
+	d.lock.Unlock()
+
+	if ok {
+		close(dc.closed)
+	}
+}
+
+// Get returns the open channel of the given ID, or nil.
+func (d *DataChannels) Get(id int) *DataChannel {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	return d.open[id]
+}
+
+// Close stops tracking the channels of the endpoint. The channels are not
+// closed.
+func (d *DataChannels) Close() error {
+	d.once.Do(func() { close(d.stop) })
+	var ret error
+	for event, id := range d.handlers {
+		if err := d.Endpoint.Unsubscribe(event, id); err != nil {
+			ret = errors.Join(ret, err)
+		}
+	}
+	return ret
+}
+
+// ConnectData connects the data channels of source to sink, e.g. two
+// WebRtcEndpoints with UseDataChannels, or a WebRtcEndpoint and a filter
+// which uses the data pads.
+func ConnectData(source, sink IMediaElement) error {
+	return source.Connect(sink, MEDIATYPE_DATA, "", "")
+}
+
+// DisconnectData disconnects the data channels of source from sink.
+func DisconnectData(source, sink IMediaElement) error {
+	return source.Disconnect(sink, MEDIATYPE_DATA, "", "")
+}
@@ -0,0 +1,192 @@
+package kurento_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// dataChannelStats answers the stats request of an opening channel with the
+// channels of the endpoint, by ID.
+func (s *script) dataChannelStats(labels map[int]string) {
+	report := make(map[string]interface{})
+	for id, label := range labels {
+		report[label] = map[string]interface{}{
+			"type":          "datachannel",
+			"label":         label,
+			"protocol":      "",
+			"datachannelid": id,
+		}
+	}
+	s.invoke("pipe/webrtc", "getStats", report)
+}
+
+func (s *script) dataChannelEvent(event string, id int) {
+	s.event("pipe/webrtc", event, map[string]interface{}{"channelId": id})
+}
+
+// dataChannels tracks the channels of the endpoint "pipe/webrtc", with the
+// requests of fn expected in between.
+func dataChannels(t *testing.T, fn func(s *script)) (*kurento.DataChannels, func()) {
+	t.Helper()
+
+	s := &script{}
+	s.subscribe("pipe/webrtc", "DataChannelOpen", "open")
+	s.subscribe("pipe/webrtc", "DataChannelClose", "close")
+	fn(s)
+	s.unsubscribe("pipe/webrtc")
+	s.unsubscribe("pipe/webrtc")
+	conn, srv := s.serve(t)
+
+	endpoint := &kurento.WebRtcEndpoint{}
+	kurento.HydrateMediaObject("pipe/webrtc", nil, conn, endpoint)
+	d, err := kurento.NewDataChannels(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d, func() {
+		t.Helper()
+		if err := d.Close(); err != nil {
+			t.Fatal(err)
+		}
+		done(t, srv)
+	}
+}
+
+func TestDataChannelOptions(t *testing.T) {
+	zero, lifeTime := 0, 3000
+	tests := []struct {
+		name    string
+		options kurento.DataChannelOptions
+		want    map[string]interface{}
+	}{
+		{
+			name:    "unordered without limits",
+			options: kurento.DataChannelOptions{Label: "chat"},
+			want:    map[string]interface{}{"label": "chat", "ordered": false, "maxPacketLifeTime": -1, "maxRetransmits": -1},
+		},
+		{
+			name:    "ordered",
+			options: kurento.DataChannelOptions{Label: "chat", Ordered: true, Protocol: "text"},
+			want:    map[string]interface{}{"ordered": true, "maxPacketLifeTime": -1, "maxRetransmits": -1, "protocol": "text"},
+		},
+		{
+			name:    "no retransmission",
+			options: kurento.DataChannelOptions{Label: "chat", MaxRetransmits: &zero},
+			want:    map[string]interface{}{"maxPacketLifeTime": -1, "maxRetransmits": 0},
+		},
+		{
+			name:    "packet life time",
+			options: kurento.DataChannelOptions{Label: "chat", MaxPacketLifeTime: &lifeTime},
+			want:    map[string]interface{}{"maxPacketLifeTime": 3000, "maxRetransmits": -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, closeAll := dataChannels(t, func(s *script) {
+				s.call("invoke", map[string]interface{}{
+					"object":          "pipe/webrtc",
+					"operation":       "createDataChannel",
+					"operationParams": tt.want,
+				}, nil)
+			})
+			if _, err := d.Create(tt.options); err != nil {
+				t.Fatal(err)
+			}
+			closeAll()
+		})
+	}
+}
+
+func TestDataChannels(t *testing.T) {
+	remote := make(chan *kurento.DataChannel, 1)
+	d, closeAll := dataChannels(t, func(s *script) {
+		s.invoke("pipe/webrtc", "createDataChannel", nil)
+		s.dataChannelEvent("DataChannelOpen", 3)
+		s.dataChannelStats(map[int]string{3: "chat"})
+		// opened by the peer
+		s.dataChannelEvent("DataChannelOpen", 4)
+		s.dataChannelStats(map[int]string{3: "chat", 4: "files"})
+		s.dataChannelEvent("DataChannelClose", 3)
+		s.invoke("pipe/webrtc", "createDataChannel", nil)
+	})
+	d.OnRemoteOpen = func(dc *kurento.DataChannel) { remote <- dc }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, err := d.Create(kurento.DataChannelOptions{Label: "chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Create(kurento.DataChannelOptions{Label: "chat"}); !errors.Is(err, kurento.ErrDataChannelPending) {
+		t.Errorf("second pending channel: %v", err)
+	}
+	if id, err := chat.WaitOpen(ctx); err != nil || id != 3 {
+		t.Fatalf("WaitOpen() = %d, %v", id, err)
+	}
+
+	files := wait(t, remote)
+	if id, _ := files.ID(); files.Label != "files" || id != 4 || d.Get(4) != files {
+		t.Errorf("remote channel %q of ID %d", files.Label, id)
+	}
+
+	wait(t, chat.Closed())
+	if d.Get(3) != nil {
+		t.Error("closed channel still tracked")
+	}
+	// the label is free once the channel opened
+	if _, err := d.Create(kurento.DataChannelOptions{Label: "chat"}); err != nil {
+		t.Fatal(err)
+	}
+	closeAll()
+}
+
+func TestDataChannelsOrder(t *testing.T) {
+	d, closeAll := dataChannels(t, func(s *script) {
+		s.invoke("pipe/webrtc", "createDataChannel", nil)
+		// closed before its stats are read, which do not list it at first
+		s.dataChannelEvent("DataChannelOpen", 3)
+		s.dataChannelEvent("DataChannelClose", 3)
+		s.dataChannelStats(map[int]string{})
+		s.dataChannelStats(map[int]string{3: "chat"})
+	})
+
+	chat, err := d.Create(kurento.DataChannelOptions{Label: "chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wait(t, chat.Opened())
+	wait(t, chat.Closed())
+	if id, _ := chat.ID(); id != 3 || d.Get(3) != nil {
+		t.Errorf("channel %d still tracked: %v", id, d.Get(3) != nil)
+	}
+	closeAll()
+}
+
+func TestDataChannelsOpenTimeout(t *testing.T) {
+	d, closeAll := dataChannels(t, func(s *script) {
+		s.invoke("pipe/webrtc", "createDataChannel", nil)
+		s.invoke("pipe/webrtc", "createDataChannel", nil)
+	})
+	d.OpenTimeout = 50 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	chat, err := d.Create(kurento.DataChannelOptions{Label: "chat"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chat.WaitOpen(ctx); err == nil || ctx.Err() != nil {
+		t.Fatalf("WaitOpen() = %v", err)
+	}
+	// the label is free once the channel expired
+	if _, err := d.Create(kurento.DataChannelOptions{Label: "chat"}); err != nil {
+		t.Fatal(err)
+	}
+	closeAll()
+}
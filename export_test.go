@@ -0,0 +1,4 @@
+package kurento
+
+// Remap exports remap to the tests.
+var Remap = remap[map[string]interface{}]
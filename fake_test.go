@@ -0,0 +1,167 @@
+package kurento_test
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+// script builds the transcript replayed by a fake server. Its requests are
+// matched on the members they give, so they only need the parameters which
+// matter to a test.
+type script struct {
+	entries []replay.Entry
+	id      int
+	// elapsed is the time of the next entries, in milliseconds. The server
+	// waits for it if it is set.
+	elapsed int64
+}
+
+// delay delays the following messages of the server by d.
+func (s *script) delay(d time.Duration) {
+	s.elapsed += d.Milliseconds()
+}
+
+func (s *script) add(dir string, message map[string]interface{}) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		panic(err)
+	}
+	s.entries = append(s.entries, replay.Entry{Elapsed: s.elapsed, Dir: dir, Message: data})
+}
+
+// request expects a request, and answers it with result.
+func (s *script) request(method string, params map[string]interface{}, result map[string]interface{}) {
+	s.id++
+	s.add(replay.Sent, map[string]interface{}{"method": method, "params": params, "id": s.id})
+	s.add(replay.Received, map[string]interface{}{"jsonrpc": "2.0", "id": s.id, "result": result})
+}
+
+// call expects a request, and answers it with value.
+func (s *script) call(method string, params map[string]interface{}, value interface{}) {
+	s.request(method, params, map[string]interface{}{"value": value})
+}
+
+// fail expects a request, and answers it with an error.
+func (s *script) fail(method string, params map[string]interface{}, code int, message string) {
+	s.id++
+	s.add(replay.Sent, map[string]interface{}{"method": method, "params": params, "id": s.id})
+	s.add(replay.Received, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      s.id,
+		"error":   map[string]interface{}{"code": code, "message": message},
+	})
+}
+
+func (s *script) create(typ, id string) {
+	s.call("create", map[string]interface{}{"type": typ}, id)
+}
+
+func (s *script) invoke(object, operation string, value interface{}) {
+	s.call("invoke", map[string]interface{}{"object": object, "operation": operation}, value)
+}
+
+func (s *script) subscribe(object, event, handler string) {
+	s.call("subscribe", map[string]interface{}{"object": object, "type": event}, handler)
+}
+
+func (s *script) unsubscribe(object string) {
+	s.call("unsubscribe", map[string]interface{}{"object": object}, nil)
+}
+
+func (s *script) release(object string) {
+	s.call("release", map[string]interface{}{"object": object}, nil)
+}
+
+// event sends an event of object, right after the previous response.
+func (s *script) event(object, event string, data map[string]interface{}) {
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	data["source"] = object
+	data["type"] = event
+	s.add(replay.Received, map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "onEvent",
+		"params": map[string]interface{}{
+			"value": map[string]interface{}{"type": event, "object": object, "data": data},
+		},
+	})
+}
+
+// serve starts a fake server replaying the script, and connects to it. The
+// test fails if a request does not match the script.
+func (s *script) serve(t *testing.T) (*kurento.Connection, *replay.Server) {
+	t.Helper()
+
+	srv := replay.NewServer(s.entries, replay.ServerOptions{Match: subset, RealTime: s.elapsed > 0})
+	ts := httptest.NewServer(srv)
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		ts.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ts.Close()
+		if err := srv.Err(); err != nil {
+			t.Error(err)
+		}
+	})
+	return conn, srv
+}
+
+// done waits until the whole script is replayed.
+func done(t *testing.T, srv *replay.Server) {
+	t.Helper()
+
+	select {
+	case <-srv.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("script not replayed")
+	}
+	if err := srv.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// subset tells if the members of expected are in got.
+func subset(expected, got map[string]interface{}) bool {
+	for k, v := range expected {
+		g, ok := got[k]
+		if !ok {
+			return false
+		}
+		e, isMap := v.(map[string]interface{})
+		m, gotMap := g.(map[string]interface{})
+		switch {
+		case isMap && gotMap:
+			if !subset(e, m) {
+				return false
+			}
+		case !reflect.DeepEqual(v, g):
+			return false
+		}
+	}
+	return true
+}
+
+// wait waits for a value of ch.
+func wait[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout")
+	}
+	var zero T
+	return zero
+}
@@ -0,0 +1,5 @@
+package kurento
+
+// The client is generated from the Kurento Module Descriptors stored in the
+// kmd directory. See cmd/kurento-gen for the naming of generated files.
+//go:generate go run ./cmd/kurento-gen -out . ./kmd
@@ -0,0 +1,275 @@
+// Package graph describes the elements of a running pipeline and the
+// connections between them, at the level of the Kurento API rather than of
+// the GStreamer elements given by GetGstreamerDot.
+//
+// A graph is read from the server with Build, and exported with DOT,
+// Mermaid, or encoding/json:
+//
+//	g, err := graph.Build(pipeline)
+//	os.WriteFile("pipeline.dot", []byte(g.DOT()), 0o644)
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// Node is an object of the pipeline.
+type Node struct {
+	Id   string `json:"id"`
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
+	// Element is false for the hubs, which have no connections.
+	Element bool `json:"element"`
+}
+
+// Edge is a connection between two elements, for one media type.
+type Edge struct {
+	Source            string            `json:"source"`
+	Sink              string            `json:"sink"`
+	Media             kurento.MediaType `json:"media"`
+	SourceDescription string            `json:"sourceDescription,omitempty"`
+	SinkDescription   string            `json:"sinkDescription,omitempty"`
+
+	// FlowingOut tells if media flows out of the source, and FlowingIn if
+	// it flows into the sink. Both are nil for data, which KMS does not
+	// monitor.
+	FlowingOut *bool `json:"flowingOut,omitempty"`
+	FlowingIn  *bool `json:"flowingIn,omitempty"`
+
+	// Transcoding tells if the source transcodes the media, nil for data.
+	Transcoding *bool `json:"transcoding,omitempty"`
+}
+
+// Flowing tells if media flows on both ends of the edge.
+func (e Edge) Flowing() bool {
+	return e.FlowingOut != nil && *e.FlowingOut && e.FlowingIn != nil && *e.FlowingIn
+}
+
+// Graph is the graph of a pipeline. Nodes and edges are sorted.
+type Graph struct {
+	Pipeline string `json:"pipeline"`
+	Nodes    []Node `json:"nodes"`
+	Edges    []Edge `json:"edges"`
+}
+
+// Build reads the graph of pipeline: its children, and the connections of
+// the elements, annotated with the media flow and transcoding states.
+// Children of types unknown to this package are left out.
+func Build(pipeline *kurento.MediaPipeline) (*Graph, error) {
+	children, err := pipeline.GetChildren()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &Graph{Pipeline: pipeline.String()}
+	edges := make(map[Edge]bool)
+	for _, child := range children {
+		node := Node{Id: child.String(), Type: kurento.TypeFromId(child.String())}
+		if named, ok := child.(interface{ GetName() (string, error) }); ok {
+			if node.Name, err = named.GetName(); err != nil {
+				return nil, err
+			}
+			if node.Name == node.Id {
+				node.Name = ""
+			}
+		}
+		elem, ok := child.(kurento.IMediaElement)
+		node.Element = ok
+		g.Nodes = append(g.Nodes, node)
+		if !ok {
+			continue
+		}
+
+		// both ends are asked, so connections to elements left out are
+		// found
+		sinks, err := elem.GetSinkConnections("", "")
+		if err != nil {
+			return nil, err
+		}
+		sources, err := elem.GetSourceConnections("", "")
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range append(sinks, sources...) {
+			edges[Edge{
+				Source:            c.Source.Id,
+				Sink:              c.Sink.Id,
+				Media:             c.Type,
+				SourceDescription: c.SourceDescription,
+				SinkDescription:   c.SinkDescription,
+			}] = true
+		}
+	}
+
+	byId := make(map[string]kurento.IMediaElement)
+	for _, child := range children {
+		if elem, ok := child.(kurento.IMediaElement); ok {
+			byId[child.String()] = elem
+		}
+	}
+	for e := range edges {
+		g.Edges = append(g.Edges, e)
+	}
+	sort.Slice(g.Nodes, func(i, j int) bool { return g.Nodes[i].Id < g.Nodes[j].Id })
+	sort.Slice(g.Edges, func(i, j int) bool {
+		a, b := g.Edges[i], g.Edges[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Sink != b.Sink {
+			return a.Sink < b.Sink
+		}
+		if a.Media != b.Media {
+			return a.Media < b.Media
+		}
+		return a.SourceDescription+a.SinkDescription < b.SourceDescription+b.SinkDescription
+	})
+
+	// the transcoding state is the same for every edge from the same pad
+	transcoding := make(map[string]*bool)
+	for i := range g.Edges {
+		e := &g.Edges[i]
+		if e.Media != kurento.MEDIATYPE_AUDIO && e.Media != kurento.MEDIATYPE_VIDEO {
+			continue
+		}
+		if source, ok := byId[e.Source]; ok {
+			if e.FlowingOut, err = flag(source.IsMediaFlowingOut(e.Media, e.SourceDescription)); err != nil {
+				return nil, err
+			}
+			key := e.Source + "|" + string(e.Media) + "|" + e.SourceDescription
+			if _, ok := transcoding[key]; !ok {
+				if transcoding[key], err = flag(source.IsMediaTranscoding(e.Media, e.SourceDescription)); err != nil {
+					return nil, err
+				}
+			}
+			e.Transcoding = transcoding[key]
+		}
+		if sink, ok := byId[e.Sink]; ok {
+			if e.FlowingIn, err = flag(sink.IsMediaFlowingIn(e.Media, e.SinkDescription)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+func flag(v bool, err error) (*bool, error) {
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// label returns the text shown for a node: its type, and its name or the
+// start of its UUID.
+func (n Node) label() string {
+	if n.Name != "" {
+		return n.Type + "\n" + n.Name
+	}
+	id := n.Id
+	if i := strings.LastIndex(id, "/"); i >= 0 {
+		id = id[i+1:]
+	}
+	if len(id) > 8 {
+		id = id[:8]
+	}
+	return n.Type + "\n" + id
+}
+
+// label returns the text shown for an edge: its media, descriptions and
+// states.
+func (e Edge) label() string {
+	parts := []string{string(e.Media)}
+	if e.SourceDescription != "" || e.SinkDescription != "" {
+		parts = append(parts, e.SourceDescription+"→"+e.SinkDescription)
+	}
+	if e.FlowingOut != nil && e.FlowingIn != nil && !e.Flowing() {
+		parts = append(parts, "not flowing")
+	}
+	if e.Transcoding != nil && *e.Transcoding {
+		parts = append(parts, "transcoding")
+	}
+	return strings.Join(parts, " ")
+}
+
+// index returns the index of the nodes by ID, adding the ends of the edges
+// which are not nodes.
+func (g *Graph) index() ([]Node, map[string]int) {
+	nodes := append([]Node(nil), g.Nodes...)
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.Id] = i
+	}
+	for _, e := range g.Edges {
+		for _, id := range []string{e.Source, e.Sink} {
+			if _, ok := index[id]; !ok {
+				index[id] = len(nodes)
+				nodes = append(nodes, Node{Id: id, Type: kurento.TypeFromId(id), Element: true})
+			}
+		}
+	}
+	return nodes, index
+}
+
+// DOT returns the graph in the Graphviz format. Edges without media flowing
+// are dashed, and transcoded media is red.
+func (g *Graph) DOT() string {
+	nodes, index := g.index()
+	var b strings.Builder
+	b.WriteString("digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n")
+	for i, n := range nodes {
+		shape := ""
+		if !n.Element {
+			shape = ", shape=ellipse"
+		}
+		fmt.Fprintf(&b, "\tn%d [label=%q%s];\n", i, n.label(), shape)
+	}
+	for _, e := range g.Edges {
+		var attrs []string
+		attrs = append(attrs, fmt.Sprintf("label=%q", e.label()))
+		if e.FlowingOut != nil && e.FlowingIn != nil && !e.Flowing() {
+			attrs = append(attrs, "style=dashed")
+		}
+		if e.Transcoding != nil && *e.Transcoding {
+			attrs = append(attrs, "color=red")
+		}
+		fmt.Fprintf(&b, "\tn%d -> n%d [%s];\n", index[e.Source], index[e.Sink], strings.Join(attrs, ", "))
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid returns the graph as a Mermaid flowchart. Edges without media
+// flowing are dotted, and transcoded media is thick.
+func (g *Graph) Mermaid() string {
+	nodes, index := g.index()
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range nodes {
+		label := strings.ReplaceAll(n.label(), "\n", "<br>")
+		if n.Element {
+			fmt.Fprintf(&b, "\tn%d[\"%s\"]\n", i, mermaidEscape(label))
+		} else {
+			fmt.Fprintf(&b, "\tn%d((\"%s\"))\n", i, mermaidEscape(label))
+		}
+	}
+	for _, e := range g.Edges {
+		arrow := "-->"
+		switch {
+		case e.Transcoding != nil && *e.Transcoding:
+			arrow = "==>"
+		case e.FlowingOut != nil && e.FlowingIn != nil && !e.Flowing():
+			arrow = "-.->"
+		}
+		fmt.Fprintf(&b, "\tn%d %s|\"%s\"| n%d\n", index[e.Source], arrow, mermaidEscape(e.label()), index[e.Sink])
+	}
+	return b.String()
+}
+
+func mermaidEscape(s string) string {
+	return strings.ReplaceAll(s, `"`, "#quot;")
+}
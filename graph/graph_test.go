@@ -0,0 +1,247 @@
+package graph
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+const (
+	pipelineId = "P_kurento.MediaPipeline"
+	playerId   = pipelineId + "/A_kurento.PlayerEndpoint"
+	webRtcId   = pipelineId + "/B_kurento.WebRtcEndpoint"
+	hubId      = pipelineId + "/C_kurento.Composite"
+)
+
+// transcript answers the invocations of calls in order, each with its value.
+func transcript(calls [][3]interface{}) []replay.Entry {
+	var entries []replay.Entry
+	add := func(dir string, message map[string]interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			panic(err)
+		}
+		entries = append(entries, replay.Entry{Dir: dir, Message: data})
+	}
+	for i, c := range calls {
+		add(replay.Sent, map[string]interface{}{
+			"method": "invoke",
+			"params": map[string]interface{}{"object": c[0], "operation": c[1]},
+		})
+		add(replay.Received, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      i + 1,
+			"result":  map[string]interface{}{"value": c[2]},
+		})
+	}
+	return entries
+}
+
+// sameCall tells if a request invokes the operation of the object expected.
+func sameCall(expected, got map[string]interface{}) bool {
+	e, _ := expected["params"].(map[string]interface{})
+	g, _ := got["params"].(map[string]interface{})
+	return expected["method"] == got["method"] && e["object"] == g["object"] && e["operation"] == g["operation"]
+}
+
+func TestBuild(t *testing.T) {
+	edge := map[string]interface{}{"source": playerId, "sink": webRtcId, "type": "VIDEO"}
+	srv := replay.NewServer(transcript([][3]interface{}{
+		{pipelineId, "getChildren", []string{playerId, webRtcId, hubId}},
+		{playerId, "getName", "player"},
+		{playerId, "getSinkConnections", []interface{}{edge}},
+		{playerId, "getSourceConnections", []interface{}{}},
+		{webRtcId, "getName", webRtcId},
+		{webRtcId, "getSinkConnections", []interface{}{}},
+		{webRtcId, "getSourceConnections", []interface{}{edge}},
+		{hubId, "getName", "mixer"},
+		{playerId, "isMediaFlowingOut", true},
+		{playerId, "isMediaTranscoding", true},
+		{webRtcId, "isMediaFlowingIn", false},
+	}), replay.ServerOptions{Match: sameCall})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	pipeline := &kurento.MediaPipeline{}
+	kurento.HydrateMediaObject(pipelineId, nil, conn, pipeline)
+	g, err := Build(pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	yes, no := true, false
+	want := &Graph{
+		Pipeline: pipelineId,
+		Nodes: []Node{
+			{Id: playerId, Type: "PlayerEndpoint", Name: "player", Element: true},
+			{Id: webRtcId, Type: "WebRtcEndpoint", Element: true},
+			{Id: hubId, Type: "Composite", Name: "mixer"},
+		},
+		Edges: []Edge{
+			{Source: playerId, Sink: webRtcId, Media: kurento.MEDIATYPE_VIDEO, FlowingOut: &yes, FlowingIn: &no, Transcoding: &yes},
+		},
+	}
+	if !reflect.DeepEqual(g, want) {
+		got, _ := json.Marshal(g)
+		expected, _ := json.Marshal(want)
+		t.Errorf("Build() = %s, want %s", got, expected)
+	}
+}
+
+func TestBuildDescriptions(t *testing.T) {
+	edge := func(description string) map[string]interface{} {
+		return map[string]interface{}{"source": playerId, "sink": webRtcId, "type": "VIDEO", "sourceDescription": description}
+	}
+	calls := [][3]interface{}{
+		{pipelineId, "getChildren", []string{playerId, webRtcId}},
+		{playerId, "getName", playerId},
+		{playerId, "getSinkConnections", []interface{}{edge("a"), edge("b")}},
+		{playerId, "getSourceConnections", []interface{}{}},
+		{webRtcId, "getName", webRtcId},
+		{webRtcId, "getSinkConnections", []interface{}{}},
+		{webRtcId, "getSourceConnections", []interface{}{edge("a"), edge("b")}},
+		{playerId, "isMediaFlowingOut", true},
+		{playerId, "isMediaTranscoding", true},
+		{webRtcId, "isMediaFlowingIn", true},
+		{playerId, "isMediaFlowingOut", true},
+		{playerId, "isMediaTranscoding", false},
+		{webRtcId, "isMediaFlowingIn", true},
+	}
+	entries := transcript(calls)
+	// the description of the edge is given to isMediaTranscoding
+	for i, description := range map[int]string{8: "a", 11: "b"} {
+		entries[2*i].Message = json.RawMessage(strings.Replace(string(entries[2*i].Message),
+			`"operation":"isMediaTranscoding"`,
+			`"operation":"isMediaTranscoding","operationParams":{"binName":"`+description+`"}`, 1))
+	}
+	srv := replay.NewServer(entries, replay.ServerOptions{Match: func(expected, got map[string]interface{}) bool {
+		e, _ := expected["params"].(map[string]interface{})
+		g, _ := got["params"].(map[string]interface{})
+		if ep, ok := e["operationParams"].(map[string]interface{}); ok {
+			gp, _ := g["operationParams"].(map[string]interface{})
+			if gp["binName"] != ep["binName"] {
+				return false
+			}
+		}
+		return sameCall(expected, got)
+	}})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	pipeline := &kurento.MediaPipeline{}
+	kurento.HydrateMediaObject(pipelineId, nil, conn, pipeline)
+	g, err := Build(pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Edges) != 2 || !*g.Edges[0].Transcoding || *g.Edges[1].Transcoding {
+		got, _ := json.Marshal(g.Edges)
+		t.Errorf("edges = %s", got)
+	}
+}
+
+func TestExport(t *testing.T) {
+	yes, no := true, false
+	player := Node{Id: playerId, Type: "PlayerEndpoint", Name: `"intro"`, Element: true}
+	webRtc := Node{Id: webRtcId, Type: "WebRtcEndpoint", Element: true}
+	hub := Node{Id: hubId, Type: "Composite"}
+
+	tests := []struct {
+		name    string
+		graph   Graph
+		dot     string
+		mermaid string
+	}{
+		{
+			name: "flowing",
+			graph: Graph{
+				Nodes: []Node{player, webRtc},
+				Edges: []Edge{{Source: playerId, Sink: webRtcId, Media: kurento.MEDIATYPE_VIDEO, FlowingOut: &yes, FlowingIn: &yes, Transcoding: &no}},
+			},
+			dot: "digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n" +
+				"\tn0 [label=\"PlayerEndpoint\\n\\\"intro\\\"\"];\n" +
+				"\tn1 [label=\"WebRtcEndpoint\\nB_kurent\"];\n" +
+				"\tn0 -> n1 [label=\"VIDEO\"];\n}\n",
+			mermaid: "flowchart LR\n" +
+				"\tn0[\"PlayerEndpoint<br>#quot;intro#quot;\"]\n" +
+				"\tn1[\"WebRtcEndpoint<br>B_kurent\"]\n" +
+				"\tn0 -->|\"VIDEO\"| n1\n",
+		},
+		{
+			name: "not flowing",
+			graph: Graph{
+				Nodes: []Node{webRtc},
+				Edges: []Edge{{Source: webRtcId, Sink: playerId, Media: kurento.MEDIATYPE_AUDIO, SourceDescription: "a", SinkDescription: "b", FlowingOut: &yes, FlowingIn: &no}},
+			},
+			dot: "digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n" +
+				"\tn0 [label=\"WebRtcEndpoint\\nB_kurent\"];\n" +
+				"\tn1 [label=\"PlayerEndpoint\\nA_kurent\"];\n" +
+				"\tn0 -> n1 [label=\"AUDIO a→b not flowing\", style=dashed];\n}\n",
+			mermaid: "flowchart LR\n" +
+				"\tn0[\"WebRtcEndpoint<br>B_kurent\"]\n" +
+				"\tn1[\"PlayerEndpoint<br>A_kurent\"]\n" +
+				"\tn0 -.->|\"AUDIO a→b not flowing\"| n1\n",
+		},
+		{
+			name: "transcoding",
+			graph: Graph{
+				Nodes: []Node{webRtc, hub},
+				Edges: []Edge{{Source: webRtcId, Sink: webRtcId, Media: kurento.MEDIATYPE_VIDEO, FlowingOut: &yes, FlowingIn: &yes, Transcoding: &yes}},
+			},
+			dot: "digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n" +
+				"\tn0 [label=\"WebRtcEndpoint\\nB_kurent\"];\n" +
+				"\tn1 [label=\"Composite\\nC_kurent\", shape=ellipse];\n" +
+				"\tn0 -> n0 [label=\"VIDEO transcoding\", color=red];\n}\n",
+			mermaid: "flowchart LR\n" +
+				"\tn0[\"WebRtcEndpoint<br>B_kurent\"]\n" +
+				"\tn1((\"Composite<br>C_kurent\"))\n" +
+				"\tn0 ==>|\"VIDEO transcoding\"| n0\n",
+		},
+		{
+			name: "data",
+			graph: Graph{
+				Nodes: []Node{player, webRtc},
+				Edges: []Edge{{Source: playerId, Sink: webRtcId, Media: kurento.MEDIATYPE_DATA}},
+			},
+			dot: "digraph pipeline {\n\trankdir=LR;\n\tnode [shape=box];\n" +
+				"\tn0 [label=\"PlayerEndpoint\\n\\\"intro\\\"\"];\n" +
+				"\tn1 [label=\"WebRtcEndpoint\\nB_kurent\"];\n" +
+				"\tn0 -> n1 [label=\"DATA\"];\n}\n",
+			mermaid: "flowchart LR\n" +
+				"\tn0[\"PlayerEndpoint<br>#quot;intro#quot;\"]\n" +
+				"\tn1[\"WebRtcEndpoint<br>B_kurent\"]\n" +
+				"\tn0 -->|\"DATA\"| n1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.graph.DOT(); got != tt.dot {
+				t.Errorf("DOT() =\n%s\nwant\n%s", got, tt.dot)
+			}
+			if got := tt.graph.Mermaid(); got != tt.mermaid {
+				t.Errorf("Mermaid() =\n%s\nwant\n%s", got, tt.mermaid)
+			}
+		})
+	}
+}
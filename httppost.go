@@ -0,0 +1,92 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// NewHttpPostEndpoint creates an HttpPostEndpoint in pipeline.
+func NewHttpPostEndpoint(pipeline *MediaPipeline, options HttpPostEndpointOptions) (*HttpPostEndpoint, error) {
+	ep := &HttpPostEndpoint{}
+	if err := pipeline.Create(ep, options.ConstructorParams()); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+// UploadSession receives a file uploaded by an HTTP client, e.g. a browser,
+// into a pipeline. The media of the file is sent to a sink element, such as
+// a RecorderEndpoint. The upload is complete when the endpoint raises
+// EndOfStream, which happens DisconnectionTimeout seconds after the last
+// request of the client.
+type UploadSession struct {
+	Endpoint *HttpPostEndpoint
+
+	// Url is where the client must POST the file.
+	Url string
+
+	handler string
+	done    chan struct{}
+	once    sync.Once
+}
+
+// NewUploadSession creates an HttpPostEndpoint in pipeline connected to
+// sink. onComplete, which may be nil, is called from the event loop of the
+// connection once the upload is complete and must not make requests to KMS.
+func NewUploadSession(pipeline *MediaPipeline, sink IMediaElement, options HttpPostEndpointOptions, onComplete func()) (*UploadSession, error) {
+	ep, err := NewHttpPostEndpoint(pipeline, options)
+	if err != nil {
+		return nil, err
+	}
+	s := &UploadSession{Endpoint: ep, done: make(chan struct{})}
+
+	s.handler, err = ep.SubscribeEndOfStream(func(EndOfStreamEvent) {
+		s.once.Do(func() {
+			close(s.done)
+			if onComplete != nil {
+				onComplete()
+			}
+		})
+	})
+	if err == nil {
+		err = ep.Connect(sink, "", "", "")
+	}
+	if err == nil {
+		s.Url, err = ep.GetUrl()
+	}
+	if err != nil {
+		ep.Release()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Done is closed once the upload is complete.
+func (s *UploadSession) Done() <-chan struct{} {
+	return s.done
+}
+
+// Wait waits until the upload is complete.
+func (s *UploadSession) Wait(ctx context.Context) error {
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close releases the endpoint. The sink is left untouched, so a recorder
+// must be stopped by the caller.
+func (s *UploadSession) Close() error {
+	var ret error
+	if s.handler != "" {
+		ret = s.Endpoint.Unsubscribe("EndOfStream", s.handler)
+		s.handler = ""
+	}
+	if err := s.Endpoint.Release(); err != nil {
+		ret = errors.Join(ret, err)
+	}
+	return ret
+}
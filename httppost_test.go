@@ -0,0 +1,95 @@
+package kurento_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestUploadSession(t *testing.T) {
+	tests := []struct {
+		name string
+		fail string
+	}{
+		{"uploaded", ""},
+		{"connect fails", "connect"},
+		{"no url", "getUrl"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := []struct {
+				name   string
+				params map[string]interface{}
+				value  interface{}
+			}{
+				{"connect", map[string]interface{}{
+					"object":          "pipe/post",
+					"operation":       "connect",
+					"operationParams": map[string]interface{}{"sink": "pipe/rec"},
+				}, nil},
+				{"getUrl", map[string]interface{}{"object": "pipe/post", "operation": "getUrl"}, "http://kms/upload"},
+			}
+
+			s := &script{}
+			s.call("create", map[string]interface{}{
+				"type": "HttpPostEndpoint",
+				"constructorParams": map[string]interface{}{
+					"mediaPipeline":        "pipe",
+					"disconnectionTimeout": 5,
+					"useEncodedMedia":      true,
+				},
+			}, "pipe/post")
+			s.subscribe("pipe/post", "EndOfStream", "eos")
+			for _, step := range steps {
+				if step.name == tt.fail {
+					s.fail("invoke", step.params, 40101, "failed")
+					break
+				}
+				s.call("invoke", step.params, step.value)
+			}
+			if tt.fail == "" {
+				// once the client stopped sending
+				s.delay(50 * time.Millisecond)
+				s.event("pipe/post", "EndOfStream", nil)
+				s.unsubscribe("pipe/post")
+			}
+			s.release("pipe/post")
+			conn, srv := s.serve(t)
+
+			pipeline, recorder := &kurento.MediaPipeline{}, &kurento.RecorderEndpoint{}
+			kurento.HydrateMediaObject("pipe", nil, conn, pipeline)
+			kurento.HydrateMediaObject("pipe/rec", nil, conn, recorder)
+			completed := make(chan bool, 1)
+			session, err := kurento.NewUploadSession(pipeline, recorder, kurento.HttpPostEndpointOptions{
+				DisconnectionTimeout: 5,
+				UseEncodedMedia:      true,
+			}, func() { completed <- true })
+			if tt.fail != "" {
+				if err == nil {
+					t.Fatal("upload session created")
+				}
+				done(t, srv)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if session.Url != "http://kms/upload" {
+				t.Errorf("url = %s", session.Url)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := session.Wait(ctx); err != nil {
+				t.Fatal(err)
+			}
+			wait(t, completed)
+			if err := session.Close(); err != nil {
+				t.Fatal(err)
+			}
+			done(t, srv)
+		})
+	}
+}
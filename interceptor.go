@@ -0,0 +1,206 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Invoker sends a request to KMS and waits for its response. The error is
+// the Error of the response, if any.
+type Invoker func(ctx context.Context, req map[string]interface{}) (Response, error)
+
+// Interceptor is called with every request of a connection. It can change
+// the request, or answer it without calling next, which sends it to the
+// following interceptor, or to KMS for the last one. An error returned
+// without a response Error is given to the caller with the RequestFailed
+// code.
+type Interceptor func(ctx context.Context, req map[string]interface{}, next Invoker) (Response, error)
+
+// Use appends interceptors to the chain of the connection, which is shared
+// by the connections made from it by WithContext. The first interceptor
+// installed is the first called.
+func (c *Connection) Use(interceptors ...Interceptor) {
+	if c.connState == nil {
+		return
+	}
+	c.chainLock.Lock()
+	defer c.chainLock.Unlock()
+
+	current, _ := c.chain.Load().([]Interceptor)
+	next := make([]Interceptor, 0, len(current)+len(interceptors))
+	next = append(append(next, current...), interceptors...)
+	c.chain.Store(next)
+}
+
+// invoke is the last Invoker of the chain.
+func (c *Connection) invoke(ctx context.Context, req map[string]interface{}) (Response, error) {
+	r := <-c.send(ctx, req)
+	if r.Error != nil {
+		return r, r.Error
+	}
+	return r, nil
+}
+
+// chain returns the invoker calling interceptors in order, then last.
+func chain(interceptors []Interceptor, last Invoker) Invoker {
+	next := last
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, following := interceptors[i], next
+		next = func(ctx context.Context, req map[string]interface{}) (Response, error) {
+			return interceptor(ctx, req, following)
+		}
+	}
+	return next
+}
+
+// errorOf converts err to the Error of a response.
+func errorOf(err error) *Error {
+	var kerr *Error
+	if errors.As(err, &kerr) {
+		return kerr
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return &Error{Code: RequestCanceled, Message: err.Error()}
+	}
+	return &Error{Code: RequestFailed, Message: err.Error()}
+}
+
+// IsIdempotent tells if req can be sent again without side effect: the
+// getters of the objects, and describe.
+func IsIdempotent(req map[string]interface{}) bool {
+	stats := newRequestStats(req)
+	switch stats.Method {
+	case "describe":
+		return true
+	case "invoke":
+		return strings.HasPrefix(stats.Operation, "get")
+	}
+	return false
+}
+
+// IsTransportError tells if err is the loss of the connection to KMS, and
+// not an error returned by KMS or by an interceptor. A lost connection is
+// not reconnected, so the request fails again on it.
+func IsTransportError(err error) bool {
+	return err != nil && errorOf(err).Code == ConnectionLost
+}
+
+// IsTimeout tells if err is an attempt of RetryInterceptor which was not
+// answered within RetryOptions.AttemptTimeout.
+func IsTimeout(err error) bool {
+	return err != nil && errorOf(err).Code == RequestTimeout
+}
+
+// LoggingInterceptor logs every request, with its duration and error, to
+// logger, or to the standard logger if nil.
+func LoggingInterceptor(logger *log.Logger) Interceptor {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(ctx context.Context, req map[string]interface{}, next Invoker) (Response, error) {
+		start := time.Now()
+		r, err := next(ctx, req)
+		stats := newRequestStats(req)
+		if err != nil {
+			logger.Printf("kurento: %s %s %s failed after %s: %v", stats.Method, stats.Operation, stats.Object, time.Since(start), err)
+		} else {
+			logger.Printf("kurento: %s %s %s in %s", stats.Method, stats.Operation, stats.Object, time.Since(start))
+		}
+		return r, err
+	}
+}
+
+// MetricsInterceptor calls fn with the stats of every request, including
+// the retries made by the interceptors after it.
+func MetricsInterceptor(fn func(RequestStats)) Interceptor {
+	return func(ctx context.Context, req map[string]interface{}, next Invoker) (Response, error) {
+		start := time.Now()
+		r, err := next(ctx, req)
+		stats := newRequestStats(req)
+		stats.Duration = time.Since(start)
+		if err != nil {
+			stats.Error = errorOf(err)
+		}
+		fn(stats)
+		return r, err
+	}
+}
+
+// RetryOptions configure RetryInterceptor.
+type RetryOptions struct {
+	// Attempts is the maximum number of times a request is sent, 3 by
+	// default.
+	Attempts int
+
+	// Backoff is the delay before the first retry, doubled at every retry,
+	// 100ms by default.
+	Backoff time.Duration
+
+	// AttemptTimeout is the time after which an attempt of a request which
+	// is retried on timeouts fails with RequestTimeout, 10s by default.
+	AttemptTimeout time.Duration
+
+	// Codes are the codes of the errors of KMS after which a request is
+	// retried, e.g. those of an overloaded server. Optional.
+	Codes []int64
+
+	// Retryable tells if the request can be sent again after err. By
+	// default, the idempotent requests are retried after a timeout or an
+	// error of Codes: the other errors of KMS, such as an unknown object or
+	// invalid parameters, and the loss of the connection would happen
+	// again. Optional.
+	Retryable func(req map[string]interface{}, err error) bool
+}
+
+// RetryInterceptor sends a request again on the same connection when it
+// fails, as allowed by options.
+func RetryInterceptor(options RetryOptions) Interceptor {
+	if options.Attempts <= 0 {
+		options.Attempts = 3
+	}
+	if options.Backoff <= 0 {
+		options.Backoff = 100 * time.Millisecond
+	}
+	if options.AttemptTimeout <= 0 {
+		options.AttemptTimeout = 10 * time.Second
+	}
+	if options.Retryable == nil {
+		options.Retryable = func(req map[string]interface{}, err error) bool {
+			if !IsIdempotent(req) {
+				return false
+			}
+			code := errorOf(err).Code
+			return code == RequestTimeout || slices.Contains(options.Codes, code)
+		}
+	}
+	timeout := &Error{Code: RequestTimeout, Message: "no response after " + options.AttemptTimeout.String()}
+	return func(ctx context.Context, req map[string]interface{}, next Invoker) (Response, error) {
+		// only the attempts retried after a timeout are bounded
+		bounded := options.Retryable(req, timeout)
+		backoff := options.Backoff
+		for attempt := 1; ; attempt++ {
+			attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+			if bounded {
+				attemptCtx, cancel = context.WithTimeout(ctx, options.AttemptTimeout)
+			}
+			r, err := next(attemptCtx, req)
+			if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+				r.Error, err = timeout, timeout
+			}
+			cancel()
+			if err == nil || attempt >= options.Attempts || !options.Retryable(req, err) {
+				return r, err
+			}
+			select {
+			case <-time.After(backoff):
+			case <-ctx.Done():
+				return r, err
+			}
+			backoff *= 2
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package kurento_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+func invokeRequest(operation string) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "invoke",
+		"params":  map[string]interface{}{"object": "elem", "operation": operation},
+	}
+}
+
+func TestRetryInterceptor(t *testing.T) {
+	lost := &kurento.Error{Code: kurento.ConnectionLost, Message: "No connection to Kurento server"}
+	invalid := &kurento.Error{Code: 40101, Message: "Object not found"}
+	busy := &kurento.Error{Code: 40001, Message: "Server busy"}
+	timeout := &kurento.Error{Code: kurento.RequestTimeout, Message: "no response"}
+
+	tests := []struct {
+		name      string
+		operation string
+		errors    []error
+		attempts  int
+		wantErr   error
+	}{
+		{"success", "getName", nil, 1, nil},
+		{"timeouts", "getName", []error{timeout, timeout}, 3, nil},
+		{"timeout at every attempt", "getName", []error{timeout, timeout, timeout}, 3, timeout},
+		{"transient error of KMS", "getName", []error{busy}, 2, nil},
+		{"error of KMS", "getName", []error{invalid}, 1, invalid},
+		// never recovered on the same connection
+		{"connection lost", "getName", []error{lost}, 1, lost},
+		{"not idempotent", "play", []error{timeout}, 1, timeout},
+		{"canceled", "getName", []error{context.Canceled}, 1, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			next := func(ctx context.Context, req map[string]interface{}) (kurento.Response, error) {
+				attempts++
+				if attempts <= len(tt.errors) {
+					return kurento.Response{}, tt.errors[attempts-1]
+				}
+				return kurento.Response{Result: map[string]interface{}{"value": "name"}}, nil
+			}
+
+			retry := kurento.RetryInterceptor(kurento.RetryOptions{Backoff: time.Millisecond, Codes: []int64{40001}})
+			_, err := retry(context.Background(), invokeRequest(tt.operation), next)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("%d attempts, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+// TestRetryTimeout checks that a request unanswered in time is sent again on
+// the same connection, and that the retry succeeds.
+func TestRetryTimeout(t *testing.T) {
+	s := &script{}
+	s.id++
+	s.add(replay.Sent, map[string]interface{}{"method": "invoke", "params": map[string]interface{}{"operation": "getName"}, "id": s.id})
+	// answered after the timeout of the attempt
+	s.delay(300 * time.Millisecond)
+	s.add(replay.Received, map[string]interface{}{"jsonrpc": "2.0", "id": s.id, "result": map[string]interface{}{"value": "late"}})
+	s.invoke("elem", "getName", "camera")
+	conn, srv := s.serve(t)
+
+	var stats []kurento.RequestStats
+	conn.Use(kurento.RetryInterceptor(kurento.RetryOptions{Backoff: time.Millisecond, AttemptTimeout: 200 * time.Millisecond}),
+		kurento.MetricsInterceptor(func(s kurento.RequestStats) { stats = append(stats, s) }))
+
+	elem := &kurento.PlayerEndpoint{}
+	kurento.HydrateMediaObject("elem", nil, conn, elem)
+	if name, err := elem.GetName(); err != nil || name != "camera" {
+		t.Errorf("GetName() = %q, %v", name, err)
+	}
+	done(t, srv)
+	if len(stats) != 2 || stats[0].Error == nil || stats[0].Error.Code != kurento.RequestCanceled || stats[1].Error != nil {
+		t.Errorf("attempts = %+v", stats)
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := []struct {
+		req  map[string]interface{}
+		want bool
+	}{
+		{invokeRequest("getStats"), true},
+		{invokeRequest("connect"), false},
+		{map[string]interface{}{"method": "describe"}, true},
+		{map[string]interface{}{"method": "create"}, false},
+	}
+	for _, tt := range tests {
+		if got := kurento.IsIdempotent(tt.req); got != tt.want {
+			t.Errorf("IsIdempotent(%v) = %v, want %v", tt.req, got, tt.want)
+		}
+	}
+}
+
+func TestInterceptorChain(t *testing.T) {
+	s := &script{}
+	s.invoke("elem", "getName", "camera")
+	s.fail("invoke", map[string]interface{}{"operation": "play"}, 40101, "Object not found")
+	conn, srv := s.serve(t)
+
+	var order []string
+	trace := func(name string) kurento.Interceptor {
+		return func(ctx context.Context, req map[string]interface{}, next kurento.Invoker) (kurento.Response, error) {
+			order = append(order, name)
+			return next(ctx, req)
+		}
+	}
+	var stats []kurento.RequestStats
+	conn.Use(trace("first"), kurento.MetricsInterceptor(func(s kurento.RequestStats) {
+		stats = append(stats, s)
+	}))
+	conn.Use(trace("second"))
+
+	elem := &kurento.PlayerEndpoint{}
+	kurento.HydrateMediaObject("elem", nil, conn, elem)
+	if name, err := elem.GetName(); err != nil || name != "camera" {
+		t.Errorf("GetName() = %q, %v", name, err)
+	}
+	if err := elem.Play(); err == nil {
+		t.Error("Play() succeeded")
+	}
+	done(t, srv)
+
+	if want := "[first second first second]"; fmt.Sprint(order) != want {
+		t.Errorf("order = %v, want %s", order, want)
+	}
+	if len(stats) != 2 || stats[0].Operation != "getName" || stats[0].Error != nil ||
+		stats[1].Operation != "play" || stats[1].Error == nil || stats[1].Error.Code != 40101 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,411 @@
+// Package metrics exports the health of a Kurento Media Server and of the
+// media elements of an application as Prometheus metrics.
+//
+// An Exporter polls the server manager for CPU and memory usage, and the
+// stats of the registered elements, and keeps the last values for the
+// scrapes. It also measures the requests made on the connection, through
+// an interceptor:
+//
+//	exp := metrics.NewExporter(conn, metrics.Options{TagLabels: []string{"room"}})
+//	prometheus.MustRegister(exp)
+//	go exp.Run(ctx)
+//
+//	exp.Register(webrtc)
+//	defer exp.Unregister(webrtc)
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// Element is a media element whose stats are exported, such as a
+// *kurento.WebRtcEndpoint or a *kurento.RtpEndpoint.
+type Element interface {
+	String() string
+	GetTags() ([]kurento.Tag, error)
+	GetStatsReport(mediaType kurento.MediaType) (kurento.StatsReport, error)
+}
+
+// Options configure an Exporter.
+type Options struct {
+	// Namespace of the metrics, "kurento" by default.
+	Namespace string
+
+	// Interval between two polls, 15 seconds by default.
+	Interval time.Duration
+
+	// CpuInterval is the time during which KMS measures the CPU usage, 1
+	// second by default.
+	CpuInterval time.Duration
+
+	// MediaTypes whose stats are polled, audio and video by default.
+	MediaTypes []kurento.MediaType
+
+	// TagLabels are the element tags exported as labels, with the same
+	// name. A missing tag gives an empty label.
+	TagLabels []string
+
+	// OnError is called with the errors of the polls. Optional.
+	OnError func(error)
+}
+
+// Exporter is a prometheus.Collector of the metrics of a connection.
+type Exporter struct {
+	conn    *kurento.Connection
+	options Options
+
+	cpu, memory                        *prometheus.Desc
+	packetsLost, jitter, rtt           *prometheus.Desc
+	bitrate, targetBitrate             *prometheus.Desc
+	latency, candidatePairs, pollError *prometheus.Desc
+
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+
+	lock     sync.Mutex
+	elements map[string]Element
+	snapshot []prometheus.Metric
+	// bytes and timestamps of the RTP streams at the previous poll, by
+	// element, media and stats ID, to compute their bitrate
+	previous map[string]streamBytes
+}
+
+type streamBytes struct {
+	bytes  int64
+	millis int64
+}
+
+// NewExporter returns an exporter of the metrics of conn, and installs the
+// interceptor measuring its requests with Use. The retries made by the
+// interceptors installed after it count as one request.
+func NewExporter(conn *kurento.Connection, options Options) *Exporter {
+	if options.Namespace == "" {
+		options.Namespace = "kurento"
+	}
+	if options.Interval <= 0 {
+		options.Interval = 15 * time.Second
+	}
+	if options.CpuInterval <= 0 {
+		options.CpuInterval = time.Second
+	}
+	if len(options.MediaTypes) == 0 {
+		options.MediaTypes = []kurento.MediaType{kurento.MEDIATYPE_AUDIO, kurento.MEDIATYPE_VIDEO}
+	}
+
+	ns := options.Namespace
+	element := append([]string{"pipeline", "element", "media"}, options.TagLabels...)
+	stream := append([]string{"direction"}, element...)
+	e := &Exporter{
+		conn:    conn,
+		options: options,
+
+		cpu: prometheus.NewDesc(ns+"_server_cpu_percent",
+			"CPU usage of the media server, averaged over its cores.", nil, nil),
+		memory: prometheus.NewDesc(ns+"_server_memory_bytes",
+			"Memory used by the media server.", nil, nil),
+		packetsLost: prometheus.NewDesc(ns+"_rtp_packets_lost_total",
+			"Packets lost by the RTP streams of the element, as reported by RTCP.", stream, nil),
+		jitter: prometheus.NewDesc(ns+"_rtp_jitter_seconds",
+			"Interarrival jitter of the inbound RTP streams of the element.", element, nil),
+		rtt: prometheus.NewDesc(ns+"_rtp_round_trip_time_seconds",
+			"Round trip time of the outbound RTP streams of the element, as reported by KMS.", element, nil),
+		bitrate: prometheus.NewDesc(ns+"_rtp_bitrate_bps",
+			"Bitrate of the RTP streams of the element between the last two polls.", stream, nil),
+		targetBitrate: prometheus.NewDesc(ns+"_rtp_target_bitrate_bps",
+			"Target bitrate of the outbound RTP streams of the element.", element, nil),
+		latency: prometheus.NewDesc(ns+"_latency_seconds",
+			"Average latency measured by the element: end-to-end for endpoints, input latency for other elements.",
+			append([]string{"kind", "stream"}, element...), nil),
+		candidatePairs: prometheus.NewDesc(ns+"_ice_candidate_pairs",
+			"ICE candidate pairs of the element, by state.", append([]string{"state"}, element...), nil),
+		pollError: prometheus.NewDesc(ns+"_poll_error",
+			"1 if the last poll of the element failed.", []string{"element"}, nil),
+
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: ns,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of the JSON-RPC requests to the media server.",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 2, 14),
+		}, []string{"method", "operation"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: ns,
+			Name:      "rpc_errors_total",
+			Help:      "JSON-RPC requests to the media server which returned an error.",
+		}, []string{"method", "operation", "code"}),
+
+		elements: make(map[string]Element),
+		previous: make(map[string]streamBytes),
+	}
+	conn.Use(kurento.MetricsInterceptor(e.ObserveRequest))
+	return e
+}
+
+// Register adds elem to the polled elements.
+func (e *Exporter) Register(elem Element) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.elements[elem.String()] = elem
+}
+
+// Unregister removes elem from the polled elements. Elements must be
+// unregistered before they are released.
+func (e *Exporter) Unregister(elem Element) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	id := elem.String()
+	delete(e.elements, id)
+	for key := range e.previous {
+		if strings.HasPrefix(key, id+"|") {
+			delete(e.previous, key)
+		}
+	}
+}
+
+// ObserveRequest records the duration and error of a request. It is
+// installed on the connection by NewExporter.
+func (e *Exporter) ObserveRequest(stats kurento.RequestStats) {
+	e.rpcDuration.WithLabelValues(stats.Method, stats.Operation).Observe(stats.Duration.Seconds())
+	if stats.Error != nil {
+		e.rpcErrors.WithLabelValues(stats.Method, stats.Operation, strconv.FormatInt(stats.Error.Code, 10)).Inc()
+	}
+}
+
+// Run polls the server every Interval until ctx is done.
+func (e *Exporter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(e.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		e.Poll()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll reads the metrics from the server, and keeps them for the next
+// scrapes.
+func (e *Exporter) Poll() {
+	var metrics []prometheus.Metric
+
+	manager := e.conn.ServerManager()
+	if cpu, err := manager.GetUsedCpu(int(e.options.CpuInterval / time.Millisecond)); err == nil {
+		metrics = append(metrics, prometheus.MustNewConstMetric(e.cpu, prometheus.GaugeValue, cpu))
+	} else {
+		e.error(err)
+	}
+	if memory, err := manager.GetUsedMemory(); err == nil {
+		metrics = append(metrics, prometheus.MustNewConstMetric(e.memory, prometheus.GaugeValue, float64(memory)*1024))
+	} else {
+		e.error(err)
+	}
+
+	e.lock.Lock()
+	elements := make([]Element, 0, len(e.elements))
+	for _, elem := range e.elements {
+		elements = append(elements, elem)
+	}
+	e.lock.Unlock()
+
+	for _, elem := range elements {
+		m, err := e.pollElement(elem)
+		failed := 0.0
+		if err != nil {
+			e.error(err)
+			failed = 1
+		}
+		metrics = append(metrics, m...)
+		metrics = append(metrics, prometheus.MustNewConstMetric(e.pollError, prometheus.GaugeValue, failed, elem.String()))
+	}
+
+	e.lock.Lock()
+	e.snapshot = metrics
+	e.lock.Unlock()
+}
+
+func (e *Exporter) error(err error) {
+	if e.options.OnError != nil {
+		e.options.OnError(err)
+	}
+}
+
+// pollElement reads the stats of elem for every media type.
+func (e *Exporter) pollElement(elem Element) ([]prometheus.Metric, error) {
+	tags, err := elem.GetTags()
+	if err != nil {
+		return nil, err
+	}
+	tagValues := make([]string, len(e.options.TagLabels))
+	for i, key := range e.options.TagLabels {
+		for _, tag := range tags {
+			if tag.Key == key {
+				tagValues[i] = tag.Value
+			}
+		}
+	}
+
+	var metrics []prometheus.Metric
+	for _, media := range e.options.MediaTypes {
+		report, err := elem.GetStatsReport(media)
+		if err != nil {
+			return metrics, err
+		}
+		labels := append([]string{pipelineId(elem.String()), elem.String(), strings.ToLower(string(media))}, tagValues...)
+		metrics = append(metrics, e.reportMetrics(elem.String()+"|"+string(media), report, labels)...)
+	}
+	return metrics, nil
+}
+
+// reportMetrics converts a stats report. key identifies the element and
+// media of the report, for the bitrates.
+func (e *Exporter) reportMetrics(key string, report kurento.StatsReport, labels []string) []prometheus.Metric {
+	var metrics []prometheus.Metric
+	metric := func(desc *prometheus.Desc, kind prometheus.ValueType, value float64, extra ...string) {
+		metrics = append(metrics, prometheus.MustNewConstMetric(desc, kind, value, append(extra, labels...)...))
+	}
+	gauge := func(desc *prometheus.Desc, value float64, extra ...string) {
+		metric(desc, prometheus.GaugeValue, value, extra...)
+	}
+
+	var lost [2]float64
+	var bitrate [2]float64
+	var jitter, rtt, targetBitrate float64
+	var inbound, outbound int
+	for i, t := range []kurento.StatsType{kurento.STATSTYPE_inboundrtp, kurento.STATSTYPE_outboundrtp} {
+		for _, id := range report.IdsOfType(t) {
+			var common kurento.Stats
+			var rtp kurento.RTCRTPStreamStats
+			if report.Decode(id, &common) != nil || report.Decode(id, &rtp) != nil {
+				continue
+			}
+			lost[i] += float64(rtp.PacketsLost)
+
+			var bytes int64
+			if i == 0 {
+				var in kurento.RTCInboundRTPStreamStats
+				if report.Decode(id, &in) != nil {
+					continue
+				}
+				bytes = in.BytesReceived
+				jitter += in.Jitter
+				inbound++
+			} else {
+				var out kurento.RTCOutboundRTPStreamStats
+				if report.Decode(id, &out) != nil {
+					continue
+				}
+				bytes = out.BytesSent
+				// in seconds
+				rtt += out.RoundTripTime
+				targetBitrate += out.TargetBitrate
+				outbound++
+			}
+			bitrate[i] += e.streamBitrate(key+"|"+id, bytes, common.TimestampMillis)
+		}
+	}
+	for i, direction := range []string{"inbound", "outbound"} {
+		if (i == 0 && inbound == 0) || (i == 1 && outbound == 0) {
+			continue
+		}
+		// KMS reports the packets lost since the start of the streams
+		metric(e.packetsLost, prometheus.CounterValue, lost[i], direction)
+		gauge(e.bitrate, bitrate[i], direction)
+	}
+	if inbound > 0 {
+		gauge(e.jitter, jitter/float64(inbound))
+	}
+	if outbound > 0 {
+		gauge(e.rtt, rtt/float64(outbound))
+		gauge(e.targetBitrate, targetBitrate)
+	}
+
+	// KMS measures the latencies in nanoseconds
+	for _, id := range report.IdsOfType(kurento.STATSTYPE_endpoint) {
+		var stats kurento.EndpointStats
+		if report.Decode(id, &stats) == nil {
+			for _, l := range stats.E2ELatency {
+				gauge(e.latency, l.Avg/1e9, "e2e", l.Name)
+			}
+		}
+	}
+	for _, id := range report.IdsOfType(kurento.STATSTYPE_element) {
+		var stats kurento.ElementStats
+		if report.Decode(id, &stats) == nil {
+			for _, l := range stats.InputLatency {
+				gauge(e.latency, l.Avg/1e9, "input", l.Name)
+			}
+		}
+	}
+
+	states := make(map[kurento.RTCStatsIceCandidatePairState]int)
+	for _, id := range report.IdsOfType(kurento.STATSTYPE_candidatepair) {
+		var stats kurento.RTCIceCandidatePairStats
+		if report.Decode(id, &stats) == nil {
+			states[stats.State]++
+		}
+	}
+	for state, n := range states {
+		gauge(e.candidatePairs, float64(n), string(state))
+	}
+	return metrics
+}
+
+// streamBitrate returns the bitrate of a stream since the previous poll, or 0 for
+// a new stream.
+func (e *Exporter) streamBitrate(key string, bytes, millis int64) float64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	prev, ok := e.previous[key]
+	e.previous[key] = streamBytes{bytes: bytes, millis: millis}
+	if !ok || millis <= prev.millis || bytes < prev.bytes {
+		return 0
+	}
+	return float64(bytes-prev.bytes) * 8 * 1000 / float64(millis-prev.millis)
+}
+
+// pipelineId returns the ID of the pipeline of an element from the ID of the
+// element.
+func pipelineId(id string) string {
+	if i := strings.Index(id, "/"); i >= 0 {
+		return id[:i]
+	}
+	return id
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		e.cpu, e.memory, e.packetsLost, e.jitter, e.rtt, e.bitrate,
+		e.targetBitrate, e.latency, e.candidatePairs, e.pollError,
+	} {
+		ch <- desc
+	}
+	e.rpcDuration.Describe(ch)
+	e.rpcErrors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector, with the values of the last
+// poll.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	e.lock.Lock()
+	snapshot := e.snapshot
+	e.lock.Unlock()
+
+	for _, m := range snapshot {
+		ch <- m
+	}
+	e.rpcDuration.Collect(ch)
+	e.rpcErrors.Collect(ch)
+}
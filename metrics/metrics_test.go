@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	kurento "github.com/safermobility/kurento-go/v6"
+	"golang.org/x/net/websocket"
+)
+
+// newTestExporter returns an exporter of a connection to a server which
+// never answers.
+func newTestExporter(t *testing.T, options Options) *Exporter {
+	t.Helper()
+
+	srv := httptest.NewServer(websocket.Handler(func(ws *websocket.Conn) {
+		io.Copy(io.Discard, ws)
+	}))
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(srv.URL, "http"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		srv.Close()
+	})
+	return NewExporter(conn, options)
+}
+
+// metricName returns the name of the metric of desc.
+func metricName(desc *prometheus.Desc) string {
+	s := desc.String()
+	s = s[strings.Index(s, `fqName: "`)+len(`fqName: "`):]
+	return s[:strings.Index(s, `"`)]
+}
+
+func TestReportMetrics(t *testing.T) {
+	report := kurento.StatsReport{
+		"in": {
+			"type":            "inboundrtp",
+			"timestampMillis": 1000,
+			"packetsLost":     3,
+			"bytesReceived":   1000,
+			"jitter":          0.02,
+		},
+		"out": {
+			"type":            "outboundrtp",
+			"timestampMillis": 1000,
+			"packetsLost":     5,
+			"bytesSent":       2000,
+			"roundTripTime":   0.1,
+			"targetBitrate":   300000,
+		},
+	}
+
+	e := newTestExporter(t, Options{})
+	metrics := e.reportMetrics("elem|VIDEO", report, []string{"pipe", "elem", "video"})
+
+	type value struct {
+		name      string
+		direction string
+	}
+	got := make(map[value]*dto.Metric)
+	for _, m := range metrics {
+		var d dto.Metric
+		if err := m.Write(&d); err != nil {
+			t.Fatal(err)
+		}
+		v := value{name: metricName(m.Desc())}
+		for _, l := range d.Label {
+			if l.GetName() == "direction" {
+				v.direction = l.GetValue()
+			}
+		}
+		got[v] = &d
+	}
+
+	tests := []struct {
+		name      string
+		direction string
+		counter   bool
+		want      float64
+	}{
+		{"kurento_rtp_packets_lost_total", "inbound", true, 3},
+		{"kurento_rtp_packets_lost_total", "outbound", true, 5},
+		{"kurento_rtp_jitter_seconds", "", false, 0.02},
+		{"kurento_rtp_round_trip_time_seconds", "", false, 0.1},
+		{"kurento_rtp_target_bitrate_bps", "", false, 300000},
+		// no previous poll
+		{"kurento_rtp_bitrate_bps", "inbound", false, 0},
+	}
+	for _, tt := range tests {
+		d, ok := got[value{tt.name, tt.direction}]
+		switch {
+		case !ok:
+			t.Errorf("%s %s: missing", tt.name, tt.direction)
+		case tt.counter && d.Counter == nil, !tt.counter && d.Gauge == nil:
+			t.Errorf("%s %s: counter is %v", tt.name, tt.direction, !tt.counter)
+		case tt.counter && d.Counter.GetValue() != tt.want, !tt.counter && d.Gauge.GetValue() != tt.want:
+			t.Errorf("%s %s = %v, want %v", tt.name, tt.direction, d, tt.want)
+		}
+	}
+}
+
+func TestStreamBitrate(t *testing.T) {
+	e := newTestExporter(t, Options{})
+	tests := []struct {
+		bytes, millis int64
+		want          float64
+	}{
+		{1000, 1000, 0},
+		{2000, 2000, 8000},
+		// the stream was restarted
+		{500, 3000, 0},
+		{1500, 3500, 16000},
+	}
+	for _, tt := range tests {
+		if got := e.streamBitrate("stream", tt.bytes, tt.millis); got != tt.want {
+			t.Errorf("streamBitrate(%d, %d) = %v, want %v", tt.bytes, tt.millis, got, tt.want)
+		}
+	}
+}
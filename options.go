@@ -0,0 +1,107 @@
+package kurento
+
+// ConstructorOptions are the typed constructor parameters of an object. Their
+// ConstructorParams can be given as options to Create.
+type ConstructorOptions interface {
+	ConstructorParams() map[string]interface{}
+}
+
+// WebRtcEndpointOptions are the constructor parameters of a WebRtcEndpoint.
+type WebRtcEndpointOptions struct {
+	// Single direction, receive-only endpoint
+	Recvonly bool
+	// Single direction, send-only endpoint
+	Sendonly bool
+	// Activate data channels support
+	UseDataChannels bool
+	// Type of certificate used for DTLS, RSA if empty
+	CertificateKeyType CertificateKeyType
+	// DSCP value of outgoing packets, none if empty
+	QosDscp DSCPValue
+}
+
+// ConstructorParams implements ConstructorOptions.
+func (o WebRtcEndpointOptions) ConstructorParams() map[string]interface{} {
+	ret := make(map[string]interface{})
+	setIfNotEmpty(ret, "recvonly", o.Recvonly)
+	setIfNotEmpty(ret, "sendonly", o.Sendonly)
+	setIfNotEmpty(ret, "useDataChannels", o.UseDataChannels)
+	setIfNotEmpty(ret, "certificateKeyType", o.CertificateKeyType)
+	setIfNotEmpty(ret, "qosDscp", o.QosDscp)
+	return ret
+}
+
+// PlayerEndpointOptions are the constructor parameters of a PlayerEndpoint.
+type PlayerEndpointOptions struct {
+	// URI pointing to the video
+	Uri string
+	// Feed the input media as-is, without decoding it
+	UseEncodedMedia bool
+	// Buffering time in ms of RTSP sources, 2000 if zero
+	NetworkCache int
+}
+
+// ConstructorParams implements ConstructorOptions.
+func (o PlayerEndpointOptions) ConstructorParams() map[string]interface{} {
+	ret := make(map[string]interface{})
+	setIfNotEmpty(ret, "uri", o.Uri)
+	setIfNotEmpty(ret, "useEncodedMedia", o.UseEncodedMedia)
+	setIfNotEmpty(ret, "networkCache", o.NetworkCache)
+	return ret
+}
+
+// HttpPostEndpointOptions are the constructor parameters of a
+// HttpPostEndpoint.
+type HttpPostEndpointOptions struct {
+	// Seconds without a new request after which the upload is considered
+	// finished, 2 if zero
+	DisconnectionTimeout int
+	// Feed the input media as-is, without decoding it
+	UseEncodedMedia bool
+}
+
+// ConstructorParams implements ConstructorOptions.
+func (o HttpPostEndpointOptions) ConstructorParams() map[string]interface{} {
+	ret := make(map[string]interface{})
+	setIfNotEmpty(ret, "disconnectionTimeout", o.DisconnectionTimeout)
+	setIfNotEmpty(ret, "useEncodedMedia", o.UseEncodedMedia)
+	return ret
+}
+
+// RecorderEndpointOptions are the constructor parameters of a
+// RecorderEndpoint.
+type RecorderEndpointOptions struct {
+	// URI where the recording will be stored
+	Uri string
+	// Format of the recording, WEBM if empty
+	MediaProfile MediaProfileSpecType
+	// Stop the recording when an end of stream is received
+	StopOnEndOfStream bool
+}
+
+// ConstructorParams implements ConstructorOptions.
+func (o RecorderEndpointOptions) ConstructorParams() map[string]interface{} {
+	ret := make(map[string]interface{})
+	setIfNotEmpty(ret, "uri", o.Uri)
+	setIfNotEmpty(ret, "mediaProfile", o.MediaProfile)
+	setIfNotEmpty(ret, "stopOnEndOfStream", o.StopOnEndOfStream)
+	return ret
+}
+
+// RtpEndpointOptions are the constructor parameters of a RtpEndpoint.
+type RtpEndpointOptions struct {
+	// SRTP configuration, plain RTP if nil. See NewSDES.
+	Crypto *SDES
+	// Use IPv6 instead of IPv4
+	UseIpv6 bool
+}
+
+// ConstructorParams implements ConstructorOptions.
+func (o RtpEndpointOptions) ConstructorParams() map[string]interface{} {
+	ret := make(map[string]interface{})
+	if o.Crypto != nil {
+		ret["crypto"] = o.Crypto.CustomSerialize()
+	}
+	setIfNotEmpty(ret, "useIpv6", o.UseIpv6)
+	return ret
+}
@@ -0,0 +1,51 @@
+package kurento_test
+
+import (
+	"reflect"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestConstructorParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		options kurento.ConstructorOptions
+		want    map[string]interface{}
+	}{
+		{"empty", kurento.WebRtcEndpointOptions{}, map[string]interface{}{}},
+		{"WebRtcEndpoint", kurento.WebRtcEndpointOptions{
+			Recvonly:           true,
+			UseDataChannels:    true,
+			CertificateKeyType: kurento.CERTIFICATEKEYTYPE_ECDSA,
+			QosDscp:            kurento.DSCPVALUE_EF,
+		}, map[string]interface{}{
+			"recvonly":           true,
+			"useDataChannels":    true,
+			"certificateKeyType": "ECDSA",
+			"qosDscp":            "EF",
+		}},
+		{"PlayerEndpoint", kurento.PlayerEndpointOptions{Uri: "rtsp://cam", NetworkCache: 500}, map[string]interface{}{
+			"uri":          "rtsp://cam",
+			"networkCache": 500,
+		}},
+		{"HttpPostEndpoint", kurento.HttpPostEndpointOptions{DisconnectionTimeout: 5}, map[string]interface{}{
+			"disconnectionTimeout": 5,
+		}},
+		{"RecorderEndpoint", kurento.RecorderEndpointOptions{
+			Uri:               "file:///tmp/a.mp4",
+			MediaProfile:      kurento.MEDIAPROFILESPECTYPE_MP4,
+			StopOnEndOfStream: true,
+		}, map[string]interface{}{
+			"uri":               "file:///tmp/a.mp4",
+			"mediaProfile":      "MP4",
+			"stopOnEndOfStream": true,
+		}},
+		{"RtpEndpoint", kurento.RtpEndpointOptions{UseIpv6: true}, map[string]interface{}{"useIpv6": true}},
+	}
+	for _, tt := range tests {
+		if got := tt.options.ConstructorParams(); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ConstructorParams() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNotSeekable    = errors.New("kurento: media is not seekable")
+	ErrSeekOutOfRange = errors.New("kurento: seek position out of the seekable range")
+)
+
+// PlaybackState is the state of a Playback.
+type PlaybackState int
+
+const (
+	// PlaybackStopped is the state of a player not started, or stopped.
+	PlaybackStopped PlaybackState = iota
+	// PlaybackPlaying is the state of a playing player.
+	PlaybackPlaying
+	// PlaybackPaused is the state of a paused player.
+	PlaybackPaused
+	// PlaybackEnded is the state after the end of the media was reached.
+	PlaybackEnded
+	// PlaybackFailed is the state after an error event of the player.
+	PlaybackFailed
+)
+
+func (s PlaybackState) String() string {
+	switch s {
+	case PlaybackStopped:
+		return "stopped"
+	case PlaybackPlaying:
+		return "playing"
+	case PlaybackPaused:
+		return "paused"
+	case PlaybackEnded:
+		return "ended"
+	case PlaybackFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("PlaybackState(%d)", int(s))
+}
+
+// PlaybackOptions configure a Playback.
+type PlaybackOptions struct {
+	// ProgressInterval is the period at which the position is polled while
+	// playing. No polling is done if zero.
+	ProgressInterval time.Duration
+
+	// OnProgress is called with the position and the duration of the media,
+	// which is zero if unknown, e.g. for live sources. It is called from the
+	// polling goroutine. Optional.
+	OnProgress func(position, duration time.Duration)
+
+	// OnStateChange is called on every change of state. It is called from
+	// the event loop of the connection and must not make requests to KMS.
+	// Optional.
+	OnStateChange func(old, new PlaybackState)
+
+	// OnError is called with the error events of the player, after the
+	// playback entered PlaybackFailed. Same restrictions as OnStateChange.
+	// Optional.
+	OnError func(ErrorEvent)
+}
+
+// Playback controls a PlayerEndpoint: it follows its state from the
+// UriEndpointStateChanged and EndOfStream events, seeks within the seekable
+// range of the media, and reports the progress of the playback.
+type Playback struct {
+	Player *PlayerEndpoint
+
+	options  PlaybackOptions
+	handlers map[string]string
+	stop     chan struct{}
+	wg       sync.WaitGroup
+
+	lock     sync.Mutex
+	state    PlaybackState
+	changed  chan struct{}
+	err      error
+	duration time.Duration
+	closed   bool
+}
+
+// NewPlayback starts following the events of player. Close must be called
+// once the playback is not needed anymore.
+func NewPlayback(player *PlayerEndpoint, options PlaybackOptions) (*Playback, error) {
+	p := &Playback{
+		Player:   player,
+		options:  options,
+		handlers: make(map[string]string),
+		stop:     make(chan struct{}),
+		changed:  make(chan struct{}),
+	}
+
+	id, err := player.SubscribeUriEndpointStateChanged(func(ev UriEndpointStateChangedEvent) {
+		switch ev.State {
+		case URIENDPOINTSTATE_START:
+			p.setState(PlaybackPlaying)
+		case URIENDPOINTSTATE_PAUSE:
+			p.setState(PlaybackPaused)
+		case URIENDPOINTSTATE_STOP:
+			p.setState(PlaybackStopped)
+		}
+	})
+	if err == nil {
+		p.handlers["UriEndpointStateChanged"] = id
+		id, err = player.SubscribeEndOfStream(func(EndOfStreamEvent) {
+			p.setState(PlaybackEnded)
+		})
+	}
+	if err == nil {
+		p.handlers["EndOfStream"] = id
+		id, err = player.SubscribeError(p.onError)
+	}
+	if err != nil {
+		p.unsubscribe()
+		return nil, err
+	}
+	p.handlers["Error"] = id
+
+	if options.ProgressInterval > 0 && options.OnProgress != nil {
+		p.wg.Add(1)
+		go p.poll()
+	}
+	return p, nil
+}
+
+func (p *Playback) onError(ev ErrorEvent) {
+	p.lock.Lock()
+	p.err = fmt.Errorf("[%d] %s", ev.ErrorCode, ev.Description)
+	p.lock.Unlock()
+
+	p.setState(PlaybackFailed)
+	if p.options.OnError != nil {
+		p.options.OnError(ev)
+	}
+}
+
+func (p *Playback) setState(state PlaybackState) {
+	p.lock.Lock()
+	old := p.state
+	if old != state {
+		p.state = state
+		close(p.changed)
+		p.changed = make(chan struct{})
+	}
+	p.lock.Unlock()
+
+	if old != state && p.options.OnStateChange != nil {
+		p.options.OnStateChange(old, state)
+	}
+}
+
+// State returns the current state, and the error which made the playback
+// fail in PlaybackFailed.
+func (p *Playback) State() (PlaybackState, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.state, p.err
+}
+
+// WaitState waits until the playback is in one of the given states, and
+// returns it.
+func (p *Playback) WaitState(ctx context.Context, states ...PlaybackState) (PlaybackState, error) {
+	for {
+		p.lock.Lock()
+		current, changed := p.state, p.changed
+		p.lock.Unlock()
+
+		for _, state := range states {
+			if state == current {
+				return current, nil
+			}
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return current, ctx.Err()
+		}
+	}
+}
+
+// Play starts or resumes the playback.
+func (p *Playback) Play() error {
+	return p.Player.Play()
+}
+
+// Pause pauses the playback.
+func (p *Playback) Pause() error {
+	return p.Player.Pause()
+}
+
+// Stop stops the playback. Play starts again from the beginning.
+func (p *Playback) Stop() error {
+	return p.Player.Stop()
+}
+
+// Info returns the seekable range and duration of the media. They are only
+// known once the player has started.
+func (p *Playback) Info() (VideoInfo, error) {
+	info, err := p.Player.GetVideoInfo()
+	if err == nil && info.Duration > 0 {
+		p.lock.Lock()
+		p.duration = time.Duration(info.Duration) * time.Millisecond
+		p.lock.Unlock()
+	}
+	return info, err
+}
+
+// Duration returns the duration of the media, zero if unknown.
+func (p *Playback) Duration() (time.Duration, error) {
+	p.lock.Lock()
+	duration := p.duration
+	p.lock.Unlock()
+	if duration > 0 {
+		return duration, nil
+	}
+
+	info, err := p.Info()
+	return time.Duration(info.Duration) * time.Millisecond, err
+}
+
+// Position returns the current position in the media.
+func (p *Playback) Position() (time.Duration, error) {
+	ms, err := p.Player.GetPosition()
+	return time.Duration(ms) * time.Millisecond, err
+}
+
+// Seek moves the playback to position, which must be within the seekable
+// range of the media.
+func (p *Playback) Seek(position time.Duration) error {
+	info, err := p.Info()
+	if err != nil {
+		return err
+	}
+	if !info.IsSeekable {
+		return ErrNotSeekable
+	}
+	ms := position.Milliseconds()
+	if ms < info.SeekableInit || ms > info.SeekableEnd {
+		return fmt.Errorf("%w: %v not in [%v, %v]", ErrSeekOutOfRange, position,
+			time.Duration(info.SeekableInit)*time.Millisecond, time.Duration(info.SeekableEnd)*time.Millisecond)
+	}
+	return p.Player.SetPosition(ms)
+}
+
+// poll reports the progress while the playback is playing.
+func (p *Playback) poll() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.options.ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-p.stop:
+			return
+		}
+
+		if state, _ := p.State(); state != PlaybackPlaying {
+			continue
+		}
+		position, err := p.Position()
+		if err != nil {
+			continue
+		}
+		duration, _ := p.Duration()
+		p.options.OnProgress(position, duration)
+	}
+}
+
+func (p *Playback) unsubscribe() error {
+	var ret error
+	for event, id := range p.handlers {
+		if err := p.Player.Unsubscribe(event, id); err != nil {
+			ret = errors.Join(ret, err)
+		}
+	}
+	return ret
+}
+
+// Close stops the progress reports and the subscriptions to the events of
+// the player. The player itself is not released.
+func (p *Playback) Close() error {
+	p.lock.Lock()
+	if p.closed {
+		p.lock.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.lock.Unlock()
+
+	close(p.stop)
+	p.wg.Wait()
+	return p.unsubscribe()
+}
@@ -0,0 +1,199 @@
+package kurento_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// playback follows the player "pipe/player", with the requests and events
+// of fn expected once subscribed.
+func playback(t *testing.T, options kurento.PlaybackOptions, fn func(s *script)) (*kurento.Playback, func()) {
+	t.Helper()
+
+	s := &script{}
+	s.subscribe("pipe/player", "UriEndpointStateChanged", "state")
+	s.subscribe("pipe/player", "EndOfStream", "eos")
+	s.subscribe("pipe/player", "Error", "error")
+	// once the last handler is registered
+	s.delay(50 * time.Millisecond)
+	fn(s)
+	for range 3 {
+		s.unsubscribe("pipe/player")
+	}
+	conn, srv := s.serve(t)
+
+	player := &kurento.PlayerEndpoint{}
+	kurento.HydrateMediaObject("pipe/player", nil, conn, player)
+	p, err := kurento.NewPlayback(player, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p, func() {
+		t.Helper()
+		if err := p.Close(); err != nil {
+			t.Fatal(err)
+		}
+		// closed once
+		if err := p.Close(); err != nil {
+			t.Fatal(err)
+		}
+		done(t, srv)
+	}
+}
+
+func (s *script) playerState(state string) {
+	s.event("pipe/player", "UriEndpointStateChanged", map[string]interface{}{"state": state})
+}
+
+func TestPlaybackTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(s *script)
+		want   []string
+		err    bool
+	}{
+		{
+			name: "played to the end",
+			script: func(s *script) {
+				s.playerState("START")
+				s.playerState("PAUSE")
+				s.playerState("START")
+				s.event("pipe/player", "EndOfStream", nil)
+			},
+			want: []string{"stopped -> playing", "playing -> paused", "paused -> playing", "playing -> ended"},
+		},
+		{
+			name: "stopped",
+			script: func(s *script) {
+				s.playerState("START")
+				// not a change
+				s.playerState("START")
+				s.playerState("STOP")
+			},
+			want: []string{"stopped -> playing", "playing -> stopped"},
+		},
+		{
+			name: "error",
+			script: func(s *script) {
+				s.playerState("START")
+				s.event("pipe/player", "Error", map[string]interface{}{"description": "no such file", "errorCode": 4})
+			},
+			want: []string{"stopped -> playing", "playing -> failed", "error 4"},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(chan string, 10)
+			p, closePlayback := playback(t, kurento.PlaybackOptions{
+				OnStateChange: func(old, new kurento.PlaybackState) { got <- fmt.Sprintf("%s -> %s", old, new) },
+				OnError:       func(ev kurento.ErrorEvent) { got <- fmt.Sprintf("error %d", ev.ErrorCode) },
+			}, tt.script)
+
+			for i, want := range tt.want {
+				if change := wait(t, got); change != want {
+					t.Errorf("change %d: %s, want %s", i, change, want)
+				}
+			}
+			state, err := p.State()
+			if (err != nil) != tt.err {
+				t.Errorf("State() = %s, %v", state, err)
+			}
+			closePlayback()
+			select {
+			case change := <-got:
+				t.Errorf("unexpected change %s", change)
+			default:
+			}
+		})
+	}
+}
+
+func TestPlaybackSeek(t *testing.T) {
+	info := func(seekable bool) map[string]interface{} {
+		return map[string]interface{}{"isSeekable": seekable, "seekableInit": 0, "seekableEnd": 60000, "duration": 60000}
+	}
+	tests := []struct {
+		name     string
+		position time.Duration
+		script   func(s *script)
+		want     error
+	}{
+		{
+			name:     "seek",
+			position: 30 * time.Second,
+			script: func(s *script) {
+				s.invoke("pipe/player", "getVideoInfo", info(true))
+				s.call("invoke", map[string]interface{}{
+					"object":          "pipe/player",
+					"operation":       "setPosition",
+					"operationParams": map[string]interface{}{"position": 30000},
+				}, nil)
+			},
+		},
+		{
+			name:     "not seekable",
+			position: 30 * time.Second,
+			script: func(s *script) {
+				s.invoke("pipe/player", "getVideoInfo", info(false))
+			},
+			want: kurento.ErrNotSeekable,
+		},
+		{
+			name:     "out of range",
+			position: 2 * time.Minute,
+			script: func(s *script) {
+				s.invoke("pipe/player", "getVideoInfo", info(true))
+			},
+			want: kurento.ErrSeekOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, closePlayback := playback(t, kurento.PlaybackOptions{}, tt.script)
+			if err := p.Seek(tt.position); !errors.Is(err, tt.want) {
+				t.Errorf("Seek() = %v, want %v", err, tt.want)
+			}
+			// known from the info of the seek
+			if d, err := p.Duration(); err != nil || d != time.Minute {
+				t.Errorf("Duration() = %v, %v", d, err)
+			}
+			closePlayback()
+		})
+	}
+}
+
+func TestPlaybackProgress(t *testing.T) {
+	progress := make(chan string, 10)
+	p, closePlayback := playback(t, kurento.PlaybackOptions{
+		ProgressInterval: 10 * time.Millisecond,
+		OnProgress: func(position, duration time.Duration) {
+			progress <- fmt.Sprintf("%v/%v", position, duration)
+		},
+	}, func(s *script) {
+		s.playerState("START")
+		s.invoke("pipe/player", "getPosition", 1500)
+		s.invoke("pipe/player", "getVideoInfo", map[string]interface{}{"duration": 0})
+		// live media: the duration is asked again
+		s.invoke("pipe/player", "getPosition", 2500)
+		s.invoke("pipe/player", "getVideoInfo", map[string]interface{}{"duration": 0})
+		s.playerState("PAUSE")
+	})
+
+	for _, want := range []string{"1.5s/0s", "2.5s/0s"} {
+		if got := wait(t, progress); got != want {
+			t.Errorf("progress %s, want %s", got, want)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := p.WaitState(ctx, kurento.PlaybackPaused); err != nil {
+		t.Fatal(err)
+	}
+	closePlayback()
+}
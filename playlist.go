@@ -0,0 +1,293 @@
+package kurento
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+)
+
+// PlaylistOptions configure a Playlist. The callbacks are called without
+// any lock held, so they can use the playlist.
+type PlaylistOptions struct {
+	// Loop starts the list again after its last item.
+	Loop bool
+
+	// Shuffle plays the items in a random order, shuffled again at every
+	// loop.
+	Shuffle bool
+
+	// Player are the constructor parameters of the players. Their Uri is
+	// replaced by the item played.
+	Player PlayerEndpointOptions
+
+	// OnItemStart is called when an item starts playing. Optional.
+	OnItemStart func(index int, uri string)
+
+	// OnItemError is called when an item cannot be played. The playlist goes
+	// on with the next item. Optional.
+	OnItemError func(index int, uri string, err error)
+
+	// OnFinished is called after the last item, when Loop is false.
+	// Optional.
+	OnFinished func()
+}
+
+// playlistItem is a player prepared for, or playing, an item.
+type playlistItem struct {
+	index    int
+	player   *PlayerEndpoint
+	handlers map[string]string
+}
+
+// Playlist plays a list of URIs to a sink element. As the URI of a player
+// cannot change, every item has its own PlayerEndpoint: the player of the
+// next item is created in advance, and swapped in on EndOfStream. Finished
+// players are released.
+type Playlist struct {
+	Pipeline *MediaPipeline
+
+	sink    IMediaElement
+	uris    []string
+	options PlaylistOptions
+
+	lock    sync.Mutex
+	order   []int
+	pos     int
+	current *playlistItem
+	next    *playlistItem
+	stopped bool
+	// callbacks of the options, run once the lock is released
+	callbacks []func()
+}
+
+// NewPlaylist prepares the playback of uris to sink, whose players are
+// created in pipeline. Playback begins with Start.
+func NewPlaylist(pipeline *MediaPipeline, sink IMediaElement, uris []string, options PlaylistOptions) (*Playlist, error) {
+	if len(uris) == 0 {
+		return nil, errors.New("kurento: empty playlist")
+	}
+	pl := &Playlist{
+		Pipeline: pipeline,
+		sink:     sink,
+		uris:     append([]string(nil), uris...),
+		options:  options,
+	}
+	pl.order = pl.newOrder()
+	return pl, nil
+}
+
+func (pl *Playlist) newOrder() []int {
+	order := make([]int, len(pl.uris))
+	for i := range order {
+		order[i] = i
+	}
+	if pl.options.Shuffle {
+		rand.Shuffle(len(order), func(i, j int) { order[i], order[j] = order[j], order[i] })
+	}
+	return order
+}
+
+// following returns the index of the item after the one at pos, and moves
+// pos to it. ok is false at the end of the list, when not looping.
+// It must be called with the lock held.
+func (pl *Playlist) following() (index int, ok bool) {
+	pl.pos++
+	if pl.pos >= len(pl.order) {
+		if !pl.options.Loop {
+			return 0, false
+		}
+		pl.order = pl.newOrder()
+		pl.pos = 0
+	}
+	return pl.order[pl.pos], true
+}
+
+// prepare creates the player of an item, and subscribes to its events.
+func (pl *Playlist) prepare(index int) (*playlistItem, error) {
+	options := pl.options.Player
+	options.Uri = pl.uris[index]
+	item := &playlistItem{index: index, player: &PlayerEndpoint{}, handlers: make(map[string]string)}
+	if err := pl.Pipeline.Create(item.player, options.ConstructorParams()); err != nil {
+		return nil, err
+	}
+
+	id, err := item.player.SubscribeEndOfStream(func(EndOfStreamEvent) {
+		// requests cannot be made from the event loop
+		go pl.advance(item, nil)
+	})
+	if err == nil {
+		item.handlers["EndOfStream"] = id
+		id, err = item.player.SubscribeError(func(ev ErrorEvent) {
+			go pl.advance(item, errors.New(ev.Description))
+		})
+	}
+	if err != nil {
+		pl.release(item)
+		return nil, err
+	}
+	item.handlers["Error"] = id
+	return item, nil
+}
+
+func (pl *Playlist) release(item *playlistItem) {
+	if item == nil {
+		return
+	}
+	for event, id := range item.handlers {
+		item.player.Unsubscribe(event, id)
+	}
+	item.player.Release()
+}
+
+// notify queues a callback of the options. It must be called with the lock
+// held.
+func (pl *Playlist) notify(cb func()) {
+	pl.callbacks = append(pl.callbacks, cb)
+}
+
+// unlock releases the lock, then runs the queued callbacks, which may call
+// the playlist.
+func (pl *Playlist) unlock() {
+	callbacks := pl.callbacks
+	pl.callbacks = nil
+	pl.lock.Unlock()
+
+	for _, cb := range callbacks {
+		cb()
+	}
+}
+
+func (pl *Playlist) itemError(index int, err error) {
+	if pl.options.OnItemError != nil {
+		uri := pl.uris[index]
+		pl.notify(func() { pl.options.OnItemError(index, uri, err) })
+	}
+}
+
+// Start plays the first item.
+func (pl *Playlist) Start() error {
+	pl.lock.Lock()
+	defer pl.unlock()
+
+	if pl.current != nil {
+		return errors.New("kurento: playlist already started")
+	}
+	pl.stopped = false
+	pl.pos = 0
+	first, err := pl.prepare(pl.order[0])
+	if err != nil {
+		return err
+	}
+	if err := pl.play(first); err != nil {
+		pl.release(first)
+		return err
+	}
+	pl.prepareNext()
+	return nil
+}
+
+// play connects the player of item to the sink and starts it. It must be
+// called with the lock held.
+func (pl *Playlist) play(item *playlistItem) error {
+	if err := item.player.Connect(pl.sink, "", "", ""); err != nil {
+		return err
+	}
+	if err := item.player.Play(); err != nil {
+		return err
+	}
+	pl.current = item
+	if pl.options.OnItemStart != nil {
+		index, uri := item.index, pl.uris[item.index]
+		pl.notify(func() { pl.options.OnItemStart(index, uri) })
+	}
+	return nil
+}
+
+// prepareNext creates the player of the next item, skipping the items
+// which fail. It must be called with the lock held.
+func (pl *Playlist) prepareNext() {
+	for attempts := 0; attempts < len(pl.uris); attempts++ {
+		index, ok := pl.following()
+		if !ok {
+			return
+		}
+		next, err := pl.prepare(index)
+		if err == nil {
+			pl.next = next
+			return
+		}
+		pl.itemError(index, err)
+	}
+}
+
+// advance swaps the prepared player in, after item ended or failed.
+func (pl *Playlist) advance(item *playlistItem, cause error) {
+	pl.lock.Lock()
+	defer pl.unlock()
+
+	if pl.stopped || pl.current != item {
+		return
+	}
+	if cause != nil {
+		pl.itemError(item.index, cause)
+	}
+
+	for attempts := 0; pl.next != nil && attempts < len(pl.uris); attempts++ {
+		next := pl.next
+		pl.next = nil
+		item.player.Disconnect(pl.sink, "", "", "")
+		err := pl.play(next)
+		if err == nil {
+			pl.release(item)
+			pl.prepareNext()
+			return
+		}
+		pl.itemError(next.index, err)
+		pl.release(next)
+		pl.prepareNext()
+	}
+
+	// end of the list, or nothing left which can be played
+	pl.release(pl.next)
+	pl.next = nil
+	pl.release(item)
+	pl.current = nil
+	if pl.options.OnFinished != nil {
+		pl.notify(pl.options.OnFinished)
+	}
+}
+
+// Skip stops the current item and plays the next one.
+func (pl *Playlist) Skip() {
+	pl.lock.Lock()
+	current := pl.current
+	pl.lock.Unlock()
+
+	if current != nil {
+		pl.advance(current, nil)
+	}
+}
+
+// Current returns the index in the list and URI of the item playing. ok is
+// false if the playlist is not playing.
+func (pl *Playlist) Current() (index int, uri string, ok bool) {
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	if pl.current == nil {
+		return 0, "", false
+	}
+	return pl.current.index, pl.uris[pl.current.index], true
+}
+
+// Stop stops the playback and releases the players.
+func (pl *Playlist) Stop() {
+	pl.lock.Lock()
+	defer pl.lock.Unlock()
+
+	pl.stopped = true
+	pl.release(pl.current)
+	pl.release(pl.next)
+	pl.current = nil
+	pl.next = nil
+}
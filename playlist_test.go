@@ -0,0 +1,151 @@
+package kurento_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// player expects the creation of the player of uri, and its subscriptions.
+func (s *script) player(uri, id string) {
+	s.call("create", map[string]interface{}{
+		"type":              "PlayerEndpoint",
+		"constructorParams": map[string]interface{}{"uri": uri},
+	}, id)
+	s.subscribe(id, "EndOfStream", id+"/eos")
+	s.subscribe(id, "Error", id+"/error")
+}
+
+// releasePlayer expects the release of a player, and of its subscriptions.
+func (s *script) releasePlayer(id string) {
+	s.unsubscribe(id)
+	s.unsubscribe(id)
+	s.release(id)
+}
+
+func TestPlaylistAdvance(t *testing.T) {
+	tests := []struct {
+		name   string
+		uris   []string
+		script func(s *script)
+		want   []string
+	}{
+		{
+			name: "end of stream",
+			uris: []string{"a", "b"},
+			script: func(s *script) {
+				s.player("a", "p1")
+				s.invoke("p1", "connect", nil)
+				s.invoke("p1", "play", nil)
+				s.player("b", "p2")
+				s.event("p1", "EndOfStream", nil)
+
+				s.invoke("p1", "disconnect", nil)
+				s.invoke("p2", "connect", nil)
+				s.invoke("p2", "play", nil)
+				s.releasePlayer("p1")
+				s.event("p2", "EndOfStream", nil)
+
+				s.releasePlayer("p2")
+			},
+			want: []string{"start 0 a (current 0)", "start 1 b (current 1)", "finished"},
+		},
+		{
+			name: "error of the next item",
+			uris: []string{"a", "b"},
+			script: func(s *script) {
+				s.player("a", "p1")
+				s.invoke("p1", "connect", nil)
+				s.invoke("p1", "play", nil)
+				s.fail("create", map[string]interface{}{"type": "PlayerEndpoint"}, 40101, "not found")
+				s.event("p1", "EndOfStream", nil)
+
+				s.releasePlayer("p1")
+			},
+			want: []string{"start 0 a (current 0)", "error 1 b", "finished"},
+		},
+		{
+			name: "error of the current item",
+			uris: []string{"a", "b"},
+			script: func(s *script) {
+				s.player("a", "p1")
+				s.invoke("p1", "connect", nil)
+				s.invoke("p1", "play", nil)
+				s.player("b", "p2")
+				s.event("p1", "Error", map[string]interface{}{"description": "decoding failed"})
+
+				s.invoke("p1", "disconnect", nil)
+				s.invoke("p2", "connect", nil)
+				s.invoke("p2", "play", nil)
+				s.releasePlayer("p1")
+				s.event("p2", "EndOfStream", nil)
+
+				s.releasePlayer("p2")
+			},
+			want: []string{"start 0 a (current 0)", "error 0 a", "start 1 b (current 1)", "finished"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &script{}
+			tt.script(s)
+			conn, srv := s.serve(t)
+
+			pipeline := &kurento.MediaPipeline{}
+			kurento.HydrateMediaObject("pipe", nil, conn, pipeline)
+			sink := &kurento.WebRtcEndpoint{}
+			kurento.HydrateMediaObject("sink", nil, conn, sink)
+
+			var lock sync.Mutex
+			var got []string
+			record := func(format string, args ...interface{}) {
+				lock.Lock()
+				defer lock.Unlock()
+				got = append(got, fmt.Sprintf(format, args...))
+			}
+			finished := make(chan struct{})
+
+			// the callbacks use the playlist, which must not be locked
+			var pl *kurento.Playlist
+			pl, err := kurento.NewPlaylist(pipeline, sink, tt.uris, kurento.PlaylistOptions{
+				OnItemStart: func(index int, uri string) {
+					current, _, _ := pl.Current()
+					record("start %d %s (current %d)", index, uri, current)
+				},
+				OnItemError: func(index int, uri string, err error) {
+					record("error %d %s", index, uri)
+				},
+				OnFinished: func() {
+					if _, _, ok := pl.Current(); ok {
+						record("still playing")
+					}
+					pl.Stop()
+					record("finished")
+					close(finished)
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := pl.Start(); err != nil {
+				t.Fatal(err)
+			}
+			wait(t, finished)
+			done(t, srv)
+
+			lock.Lock()
+			defer lock.Unlock()
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPlaylistEmpty(t *testing.T) {
+	if _, err := kurento.NewPlaylist(&kurento.MediaPipeline{}, &kurento.WebRtcEndpoint{}, nil, kurento.PlaylistOptions{}); err == nil {
+		t.Error("empty playlist accepted")
+	}
+}
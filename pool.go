@@ -0,0 +1,411 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrNoServer      = errors.New("kurento: no server available")
+	ErrUnknownServer = errors.New("kurento: unknown server")
+)
+
+// ServerLoad is the load of a server, as measured by the last health check
+// of a Pool.
+type ServerLoad struct {
+	CpuCount int
+	// UsedCpu is the CPU usage in percent, averaged over the cores.
+	UsedCpu float64
+	// UsedMemory is in KiB.
+	UsedMemory int64
+	// Pipelines is the number of pipelines of the server.
+	Pipelines int
+	// Placed is the number of pipelines placed on the server by the pool
+	// since the last check.
+	Placed int
+}
+
+// PoolServer is the state of a server of a Pool.
+type PoolServer struct {
+	Url  string
+	Conn *Connection
+
+	Healthy bool
+	// Draining servers get no new pipelines.
+	Draining bool
+	Load     ServerLoad
+
+	LastCheck time.Time
+	// Err is the error of the last check, if it failed.
+	Err error
+}
+
+// Placement chooses the server of a new pipeline. Place is called with the
+// healthy servers which are not draining, and returns the index of the
+// chosen one, or -1 if none fits.
+type Placement interface {
+	Place(key string, servers []PoolServer) int
+}
+
+// LeastLoaded places pipelines on the server with the lowest CPU usage,
+// then the lowest memory usage. The pipelines placed since the last check
+// count as PipelineWeight percents of a core each.
+type LeastLoaded struct {
+	// PipelineWeight is 25 by default.
+	PipelineWeight float64
+}
+
+func (l *LeastLoaded) load(s PoolServer) float64 {
+	weight := l.PipelineWeight
+	if weight <= 0 {
+		weight = 25
+	}
+	cpus := s.Load.CpuCount
+	if cpus <= 0 {
+		cpus = 1
+	}
+	return s.Load.UsedCpu + weight*float64(s.Load.Placed)/float64(cpus)
+}
+
+// Place implements Placement.
+func (l *LeastLoaded) Place(key string, servers []PoolServer) int {
+	best := -1
+	for i, s := range servers {
+		if best < 0 {
+			best = i
+			continue
+		}
+		a, b := l.load(s), l.load(servers[best])
+		if a < b || (a == b && s.Load.UsedMemory < servers[best].Load.UsedMemory) {
+			best = i
+		}
+	}
+	return best
+}
+
+// RoundRobin places pipelines on the servers in turn.
+type RoundRobin struct {
+	next atomic.Uint64
+}
+
+// Place implements Placement.
+func (r *RoundRobin) Place(key string, servers []PoolServer) int {
+	if len(servers) == 0 {
+		return -1
+	}
+	return int((r.next.Add(1) - 1) % uint64(len(servers)))
+}
+
+// Sticky places the pipelines of a key, e.g. a room, on the same server,
+// chosen by Fallback for the first one or when the server is unavailable.
+type Sticky struct {
+	Fallback Placement
+
+	lock    sync.Mutex
+	servers map[string]string
+}
+
+// NewSticky returns a sticky placement, falling back to fallback.
+func NewSticky(fallback Placement) *Sticky {
+	return &Sticky{Fallback: fallback, servers: make(map[string]string)}
+}
+
+// Place implements Placement. Pipelines without a key are placed by
+// Fallback.
+func (s *Sticky) Place(key string, servers []PoolServer) int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if url, ok := s.servers[key]; ok && key != "" {
+		for i, server := range servers {
+			if server.Url == url {
+				return i
+			}
+		}
+	}
+	i := s.Fallback.Place(key, servers)
+	if i >= 0 && key != "" {
+		s.servers[key] = servers[i].Url
+	}
+	return i
+}
+
+// Forget removes the server of key, e.g. when the room is closed.
+func (s *Sticky) Forget(key string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	delete(s.servers, key)
+}
+
+// PoolOptions configure a Pool.
+type PoolOptions struct {
+	// Placement chooses the servers, LeastLoaded by default.
+	Placement Placement
+
+	// CheckInterval is the interval between two health checks, 10 seconds
+	// by default.
+	CheckInterval time.Duration
+
+	// CheckTimeout is the time after which a server which does not answer
+	// a check is unhealthy, 5 seconds by default.
+	CheckTimeout time.Duration
+
+	// CpuInterval is the time during which KMS measures the CPU usage,
+	// 500ms by default.
+	CpuInterval time.Duration
+
+	// OnStateChange is called when a server becomes healthy or unhealthy.
+	// Optional.
+	OnStateChange func(PoolServer)
+}
+
+// Pool places pipelines on several servers. The servers are checked
+// periodically by Run: unhealthy servers get no pipelines, and are
+// reconnected when their connection is lost.
+type Pool struct {
+	options PoolOptions
+
+	lock    sync.Mutex
+	servers []*PoolServer
+	// checks serializes the checks of each server, so that overlapping
+	// calls of Check do not reconnect it twice.
+	checks map[*PoolServer]*sync.Mutex
+}
+
+// NewPool connects to the servers of urls and checks them. It fails only if
+// no server can be reached.
+func NewPool(urls []string, options PoolOptions) (*Pool, error) {
+	if options.Placement == nil {
+		options.Placement = &LeastLoaded{}
+	}
+	if options.CheckInterval <= 0 {
+		options.CheckInterval = 10 * time.Second
+	}
+	if options.CheckTimeout <= 0 {
+		options.CheckTimeout = 5 * time.Second
+	}
+	if options.CpuInterval <= 0 {
+		options.CpuInterval = 500 * time.Millisecond
+	}
+
+	p := &Pool{options: options, checks: make(map[*PoolServer]*sync.Mutex)}
+	for _, url := range urls {
+		s := &PoolServer{Url: url}
+		p.servers = append(p.servers, s)
+		p.checks[s] = &sync.Mutex{}
+	}
+	p.Check()
+	for _, s := range p.Servers() {
+		if s.Healthy {
+			return p, nil
+		}
+	}
+	p.Close()
+	return nil, ErrNoServer
+}
+
+// Run checks the servers every CheckInterval until ctx is done.
+func (p *Pool) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.options.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.Check()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Check checks all the servers at once. A server being checked by another
+// call is checked again once that check is over.
+func (p *Pool) Check() {
+	p.lock.Lock()
+	servers := append([]*PoolServer(nil), p.servers...)
+	p.lock.Unlock()
+
+	var wg sync.WaitGroup
+	for _, s := range servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.check(s)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Pool) check(s *PoolServer) {
+	p.checks[s].Lock()
+	defer p.checks[s].Unlock()
+
+	p.lock.Lock()
+	url, conn := s.Url, s.Conn
+	p.lock.Unlock()
+
+	var err error
+	if conn.Lost() {
+		if conn != nil {
+			conn.Close()
+		}
+		conn, err = NewConnection(url)
+	}
+	var load ServerLoad
+	if err == nil {
+		load, err = p.measure(conn)
+	}
+
+	p.lock.Lock()
+	wasHealthy := s.Healthy
+	s.Conn = conn
+	s.LastCheck = time.Now()
+	s.Err = err
+	s.Healthy = err == nil
+	if err == nil {
+		s.Load = load
+	}
+	state := *s
+	p.lock.Unlock()
+
+	if wasHealthy != state.Healthy && p.options.OnStateChange != nil {
+		p.options.OnStateChange(state)
+	}
+}
+
+// measure reads the load of the server of conn.
+func (p *Pool) measure(conn *Connection) (ServerLoad, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), p.options.CheckTimeout+p.options.CpuInterval)
+	defer cancel()
+
+	var load ServerLoad
+	var err error
+	manager := conn.WithContext(ctx).ServerManager()
+	if load.CpuCount, err = manager.GetCpuCount(); err != nil {
+		return load, err
+	}
+	if load.UsedCpu, err = manager.GetUsedCpu(int(p.options.CpuInterval / time.Millisecond)); err != nil {
+		return load, err
+	}
+	if load.UsedMemory, err = manager.GetUsedMemory(); err != nil {
+		return load, err
+	}
+	pipelines, err := manager.GetPipelines()
+	load.Pipelines = len(pipelines)
+	return load, err
+}
+
+// Servers returns the state of the servers, sorted by URL.
+func (p *Pool) Servers() []PoolServer {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	ret := make([]PoolServer, len(p.servers))
+	for i, s := range p.servers {
+		ret[i] = *s
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Url < ret[j].Url })
+	return ret
+}
+
+// NewMediaPipeline creates a pipeline on the server chosen by the placement
+// for key, which may be empty. If the creation fails, the server is marked
+// unhealthy until its next check, and another one is tried.
+func (p *Pool) NewMediaPipeline(key string) (*MediaPipeline, error) {
+	for {
+		p.lock.Lock()
+		var available []PoolServer
+		var servers []*PoolServer
+		for _, s := range p.servers {
+			if s.Healthy && !s.Draining {
+				available = append(available, *s)
+				servers = append(servers, s)
+			}
+		}
+		i := -1
+		if len(available) > 0 {
+			i = p.options.Placement.Place(key, available)
+		}
+		if i < 0 || i >= len(servers) {
+			p.lock.Unlock()
+			return nil, ErrNoServer
+		}
+		s := servers[i]
+		s.Load.Placed++
+		conn := s.Conn
+		p.lock.Unlock()
+
+		pipeline := &MediaPipeline{}
+		err := conn.Create(pipeline, nil)
+		if err == nil {
+			return pipeline, nil
+		}
+
+		p.lock.Lock()
+		s.Load.Placed--
+		s.Healthy = false
+		s.Err = fmt.Errorf("kurento: creating a pipeline: %w", err)
+		state := *s
+		p.lock.Unlock()
+		if p.options.OnStateChange != nil {
+			p.options.OnStateChange(state)
+		}
+	}
+}
+
+func (p *Pool) server(url string) (*PoolServer, error) {
+	for _, s := range p.servers {
+		if s.Url == url {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownServer, url)
+}
+
+// Drain stops placing pipelines on the server of url. Its pipelines are
+// left untouched: it can be stopped once Load.Pipelines drops to 0.
+func (p *Pool) Drain(url string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s, err := p.server(url)
+	if err == nil {
+		s.Draining = true
+	}
+	return err
+}
+
+// Resume places pipelines on a drained server again.
+func (p *Pool) Resume(url string) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s, err := p.server(url)
+	if err == nil {
+		s.Draining = false
+	}
+	return err
+}
+
+// Close closes the connections to the servers.
+func (p *Pool) Close() error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	var ret error
+	for _, s := range p.servers {
+		if s.Conn != nil {
+			ret = errors.Join(ret, s.Conn.Close())
+			s.Conn = nil
+		}
+		s.Healthy = false
+	}
+	return ret
+}
@@ -0,0 +1,193 @@
+package kurento_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"golang.org/x/net/websocket"
+)
+
+func TestPlacement(t *testing.T) {
+	servers := []kurento.PoolServer{
+		{Url: "ws://a", Load: kurento.ServerLoad{CpuCount: 4, UsedCpu: 50, UsedMemory: 100}},
+		{Url: "ws://b", Load: kurento.ServerLoad{CpuCount: 4, UsedCpu: 20, UsedMemory: 300}},
+		{Url: "ws://c", Load: kurento.ServerLoad{CpuCount: 2, UsedCpu: 20, UsedMemory: 200}},
+	}
+	placed := func(i, n int) []kurento.PoolServer {
+		ret := append([]kurento.PoolServer(nil), servers...)
+		ret[i].Load.Placed = n
+		return ret
+	}
+
+	tests := []struct {
+		name      string
+		placement kurento.Placement
+		servers   [][]kurento.PoolServer
+		keys      []string
+		want      []int
+	}{
+		{
+			name:      "least loaded",
+			placement: &kurento.LeastLoaded{},
+			servers:   [][]kurento.PoolServer{servers},
+			want:      []int{2},
+		},
+		{
+			name:      "least loaded with placed pipelines",
+			placement: &kurento.LeastLoaded{},
+			// 20% + 25% / 2 cores for c, 20% + 0 for b
+			servers: [][]kurento.PoolServer{placed(2, 1)},
+			want:    []int{1},
+		},
+		{
+			name:      "least loaded weight",
+			placement: &kurento.LeastLoaded{PipelineWeight: 100},
+			// 20% + 2 * 100% / 4 cores for b
+			servers: [][]kurento.PoolServer{placed(1, 2)},
+			want:    []int{2},
+		},
+		{
+			name:      "least loaded without servers",
+			placement: &kurento.LeastLoaded{},
+			servers:   [][]kurento.PoolServer{nil},
+			want:      []int{-1},
+		},
+		{
+			name:      "round robin",
+			placement: &kurento.RoundRobin{},
+			servers:   [][]kurento.PoolServer{servers, servers, servers, servers},
+			want:      []int{0, 1, 2, 0},
+		},
+		{
+			name:      "sticky",
+			placement: kurento.NewSticky(&kurento.RoundRobin{}),
+			servers:   [][]kurento.PoolServer{servers, servers, servers, servers},
+			keys:      []string{"room1", "room2", "room1", ""},
+			want:      []int{0, 1, 0, 2},
+		},
+		{
+			name:      "sticky server unavailable",
+			placement: kurento.NewSticky(&kurento.LeastLoaded{}),
+			servers:   [][]kurento.PoolServer{servers, servers[:2], servers},
+			keys:      []string{"room", "room", "room"},
+			want:      []int{2, 1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int
+			for i, s := range tt.servers {
+				key := ""
+				if i < len(tt.keys) {
+					key = tt.keys[i]
+				}
+				got = append(got, tt.placement.Place(key, s))
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("placed on %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// loadServer is a fake KMS answering the checks of a Pool. It counts the
+// connections, and can drop them.
+type loadServer struct {
+	connections atomic.Int32
+
+	lock sync.Mutex
+	ws   []*websocket.Conn
+}
+
+func (s *loadServer) serve(ws *websocket.Conn) {
+	s.connections.Add(1)
+	s.lock.Lock()
+	s.ws = append(s.ws, ws)
+	s.lock.Unlock()
+
+	values := map[string]interface{}{
+		"getCpuCount":   2,
+		"getUsedCpu":    10.5,
+		"getUsedMemory": 1024,
+		"getPipelines":  []string{},
+	}
+	for {
+		var req struct {
+			Id     int64
+			Params struct{ Operation string }
+		}
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			return
+		}
+		resp := map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.Id,
+			"result":  map[string]interface{}{"value": values[req.Params.Operation]},
+		}
+		data, _ := json.Marshal(resp)
+		if err := websocket.Message.Send(ws, string(data)); err != nil {
+			return
+		}
+	}
+}
+
+// drop closes the connections of the clients.
+func (s *loadServer) drop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, ws := range s.ws {
+		ws.Close()
+	}
+	s.ws = nil
+}
+
+// TestPoolReconnect checks that overlapping checks reconnect a lost server
+// once.
+func TestPoolReconnect(t *testing.T) {
+	kms := &loadServer{}
+	ts := httptest.NewServer(websocket.Handler(kms.serve))
+	defer ts.Close()
+
+	pool, err := kurento.NewPool([]string{"ws" + strings.TrimPrefix(ts.URL, "http")}, kurento.PoolOptions{
+		CpuInterval: time.Millisecond,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pool.Close()
+	if s := pool.Servers()[0]; !s.Healthy || s.Load.CpuCount != 2 || s.Load.UsedMemory != 1024 {
+		t.Fatalf("server = %+v", s)
+	}
+
+	lost := pool.Servers()[0].Conn
+	kms.drop()
+	wait(t, lost.Dead)
+	if !lost.Lost() {
+		t.Fatal("connection not lost")
+	}
+
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pool.Check()
+		}()
+	}
+	wg.Wait()
+
+	if n := kms.connections.Load(); n != 2 {
+		t.Errorf("%d connections, want 2", n)
+	}
+	if s := pool.Servers()[0]; !s.Healthy || s.Conn == lost || s.Conn.Lost() {
+		t.Errorf("server = %+v", s)
+	}
+}
@@ -0,0 +1,403 @@
+// Package reaper releases the pipelines left behind on a Kurento Media
+// Server by crashed application servers.
+//
+// A Reaper installs an interceptor on its connection which tags every
+// object created with the owner ID of the application, and the pipelines
+// with a heartbeat timestamp, refreshed while the application runs. Every
+// application sharing the server scans the pipelines, and releases those
+// whose owner stopped its heartbeat, or where no media flows:
+//
+//	r := reaper.New(conn, reaper.Options{IdleAfter: 30 * time.Minute})
+//	go r.Run(ctx)
+//
+// Pipelines without an owner tag are never released.
+package reaper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// Tags set on the objects.
+const (
+	OwnerTag     = "kurento-go.owner"
+	HeartbeatTag = "kurento-go.heartbeat"
+)
+
+// Reason tells why a pipeline is released.
+type Reason int
+
+const (
+	// ReasonStale is the reason of the pipelines whose owner heartbeat is
+	// older than StaleAfter.
+	ReasonStale Reason = iota + 1
+	// ReasonIdle is the reason of the pipelines without media flowing for
+	// IdleAfter.
+	ReasonIdle
+)
+
+func (r Reason) String() string {
+	switch r {
+	case ReasonStale:
+		return "stale"
+	case ReasonIdle:
+		return "idle"
+	}
+	return "unknown"
+}
+
+// Options configure a Reaper.
+type Options struct {
+	// Owner identifies the application in the tags, the host name and
+	// process ID by default. Pipelines are only kept alive by the reaper
+	// which created them: those of a previous run with the same owner, e.g.
+	// a restarted container, are reaped once their heartbeat is stale.
+	Owner string
+
+	// HeartbeatInterval is the interval between two heartbeats of the
+	// pipelines of the application, 30 seconds by default.
+	HeartbeatInterval time.Duration
+
+	// StaleAfter is the age after which a heartbeat is stale, 3 heartbeat
+	// intervals by default.
+	StaleAfter time.Duration
+
+	// ClockSkew is the largest difference between the clocks of the hosts
+	// sharing the server, 1 minute by default. Heartbeats are timestamps of
+	// the clock of their owner, so a heartbeat is only stale once older than
+	// StaleAfter plus ClockSkew.
+	ClockSkew time.Duration
+
+	// IdleAfter is the time without media flowing after which a pipeline is
+	// released, including those of the application. 0 never releases idle
+	// pipelines.
+	IdleAfter time.Duration
+
+	// ScanInterval is the interval between two scans of the pipelines, 1
+	// minute by default.
+	ScanInterval time.Duration
+
+	// DryRun reports the pipelines to release without releasing them.
+	DryRun bool
+
+	// OnRelease is called with every pipeline released, or which would be
+	// released in dry run. Optional.
+	OnRelease func(Candidate)
+
+	// OnError is called with the errors of the heartbeats and scans.
+	// Optional.
+	OnError func(error)
+}
+
+// Candidate is a pipeline found by a scan.
+type Candidate struct {
+	Pipeline  string
+	Owner     string
+	Heartbeat time.Time
+	Reason    Reason
+	// Released is false in dry run, or if the release failed with Err.
+	Released bool
+	Err      error
+}
+
+// Reaper tags the objects of a connection, and releases the leaked
+// pipelines of the server.
+type Reaper struct {
+	conn    *kurento.Connection
+	options Options
+
+	lock sync.Mutex
+	// pipelines created by this reaper's connection
+	owned map[string]bool
+	// last time media was seen flowing in each pipeline
+	active map[string]time.Time
+}
+
+// New returns a reaper for the server of conn, and installs the tagging
+// interceptor on conn.
+func New(conn *kurento.Connection, options Options) *Reaper {
+	if options.Owner == "" {
+		host, _ := os.Hostname()
+		options.Owner = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	if options.HeartbeatInterval <= 0 {
+		options.HeartbeatInterval = 30 * time.Second
+	}
+	if options.StaleAfter <= 0 {
+		options.StaleAfter = 3 * options.HeartbeatInterval
+	}
+	if options.ClockSkew <= 0 {
+		options.ClockSkew = time.Minute
+	}
+	if options.ScanInterval <= 0 {
+		options.ScanInterval = time.Minute
+	}
+	r := &Reaper{
+		conn:    conn,
+		options: options,
+		owned:   make(map[string]bool),
+		active:  make(map[string]time.Time),
+	}
+	conn.Use(r.intercept)
+	return r
+}
+
+func (r *Reaper) error(err error) {
+	if r.options.OnError != nil {
+		r.options.OnError(err)
+	}
+}
+
+func addTagRequest(object, key, value string) map[string]interface{} {
+	return map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  "invoke",
+		"params": map[string]interface{}{
+			"object":          object,
+			"operation":       "addTag",
+			"operationParams": map[string]interface{}{"key": key, "value": value},
+		},
+	}
+}
+
+// intercept tags the created objects, and follows the pipelines of the
+// application.
+func (r *Reaper) intercept(ctx context.Context, req map[string]interface{}, next kurento.Invoker) (kurento.Response, error) {
+	resp, err := next(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+	method, _ := req["method"].(string)
+	params, _ := req["params"].(map[string]interface{})
+	switch method {
+	case "create":
+		id, _ := resp.Result["value"].(string)
+		if id == "" {
+			break
+		}
+		tags := [][2]string{{OwnerTag, r.options.Owner}}
+		if params["type"] == "MediaPipeline" {
+			r.lock.Lock()
+			r.owned[id] = true
+			r.lock.Unlock()
+			// the heartbeat is set first, so a scan never sees an owner
+			// without it
+			tags = append([][2]string{{HeartbeatTag, heartbeat()}}, tags...)
+		}
+		for _, tag := range tags {
+			if _, tagErr := next(ctx, addTagRequest(id, tag[0], tag[1])); tagErr != nil {
+				r.error(fmt.Errorf("reaper: tagging %s: %w", id, tagErr))
+				break
+			}
+		}
+	case "release":
+		id, _ := params["object"].(string)
+		r.forget(id)
+	}
+	return resp, nil
+}
+
+func heartbeat() string {
+	return strconv.FormatInt(time.Now().Unix(), 10)
+}
+
+func (r *Reaper) forget(pipeline string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	delete(r.owned, pipeline)
+	delete(r.active, pipeline)
+}
+
+// Run sends the heartbeats and scans the pipelines until ctx is done.
+func (r *Reaper) Run(ctx context.Context) error {
+	heartbeats := time.NewTicker(r.options.HeartbeatInterval)
+	defer heartbeats.Stop()
+	scans := time.NewTicker(r.options.ScanInterval)
+	defer scans.Stop()
+
+	for {
+		select {
+		case <-heartbeats.C:
+			if err := r.Heartbeat(); err != nil {
+				r.error(err)
+			}
+		case <-scans.C:
+			if _, err := r.Scan(); err != nil {
+				r.error(err)
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Heartbeat refreshes the heartbeat of the pipelines of the application.
+func (r *Reaper) Heartbeat() error {
+	r.lock.Lock()
+	owned := make([]string, 0, len(r.owned))
+	for id := range r.owned {
+		owned = append(owned, id)
+	}
+	r.lock.Unlock()
+
+	var ret error
+	now := heartbeat()
+	for _, id := range owned {
+		pipeline := &kurento.MediaPipeline{}
+		if err := kurento.HydrateMediaObject(id, nil, r.conn, pipeline); err != nil {
+			ret = errors.Join(ret, fmt.Errorf("reaper: heartbeat of %s: %w", id, err))
+			continue
+		}
+		if err := pipeline.AddTag(HeartbeatTag, now); err != nil {
+			ret = errors.Join(ret, fmt.Errorf("reaper: heartbeat of %s: %w", id, err))
+		}
+	}
+	return ret
+}
+
+// Scan lists the pipelines of the server, and releases the leaked ones,
+// unless in dry run. It returns the pipelines released, or to release.
+func (r *Reaper) Scan() ([]Candidate, error) {
+	pipelines, err := r.conn.ServerManager().GetPipelines()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []Candidate
+	var errs error
+	seen := make(map[string]bool)
+	for _, p := range pipelines {
+		pipeline, ok := p.(*kurento.MediaPipeline)
+		if !ok {
+			continue
+		}
+		seen[pipeline.String()] = true
+		c, err := r.check(pipeline)
+		if err != nil {
+			errs = errors.Join(errs, fmt.Errorf("reaper: %s: %w", pipeline, err))
+			continue
+		}
+		if c == nil {
+			continue
+		}
+		if !r.options.DryRun {
+			c.Err = pipeline.Release()
+			c.Released = c.Err == nil
+			if c.Released {
+				r.forget(c.Pipeline)
+			}
+		}
+		if r.options.OnRelease != nil {
+			r.options.OnRelease(*c)
+		}
+		ret = append(ret, *c)
+	}
+
+	// pipelines released by others
+	r.lock.Lock()
+	for id := range r.active {
+		if !seen[id] {
+			delete(r.active, id)
+		}
+	}
+	r.lock.Unlock()
+	return ret, errs
+}
+
+// check returns the candidate for the release of pipeline, or nil if it is
+// not leaked.
+func (r *Reaper) check(pipeline *kurento.MediaPipeline) (*Candidate, error) {
+	tags, err := pipeline.GetTags()
+	if err != nil {
+		return nil, err
+	}
+	c := &Candidate{Pipeline: pipeline.String()}
+	for _, tag := range tags {
+		switch tag.Key {
+		case OwnerTag:
+			c.Owner = tag.Value
+		case HeartbeatTag:
+			if sec, err := strconv.ParseInt(tag.Value, 10, 64); err == nil {
+				c.Heartbeat = time.Unix(sec, 0)
+			}
+		}
+	}
+	if c.Owner == "" {
+		return nil, nil
+	}
+
+	r.lock.Lock()
+	owned := r.owned[c.Pipeline]
+	r.lock.Unlock()
+	if !owned && time.Since(c.Heartbeat) > r.options.StaleAfter+r.options.ClockSkew {
+		c.Reason = ReasonStale
+		return c, nil
+	}
+	if r.options.IdleAfter > 0 {
+		idle, err := r.idle(pipeline)
+		if err != nil {
+			return nil, err
+		}
+		if idle {
+			c.Reason = ReasonIdle
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// idle tells if no media has flowed in pipeline for IdleAfter, counting
+// from the first scan which saw it.
+func (r *Reaper) idle(pipeline *kurento.MediaPipeline) (bool, error) {
+	flowing, err := mediaFlowing(pipeline)
+	if err != nil {
+		return false, err
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	last, ok := r.active[pipeline.String()]
+	if flowing || !ok {
+		r.active[pipeline.String()] = time.Now()
+		return false, nil
+	}
+	return time.Since(last) > r.options.IdleAfter, nil
+}
+
+// mediaFlowing tells if audio or video flows in or out of an element of
+// pipeline.
+func mediaFlowing(pipeline *kurento.MediaPipeline) (bool, error) {
+	children, err := pipeline.GetChildren()
+	if err != nil {
+		return false, err
+	}
+	for _, child := range children {
+		elem, ok := child.(kurento.IMediaElement)
+		if !ok {
+			continue
+		}
+		for _, media := range []kurento.MediaType{kurento.MEDIATYPE_AUDIO, kurento.MEDIATYPE_VIDEO} {
+			in, err := elem.IsMediaFlowingIn(media, "")
+			if err != nil {
+				return false, err
+			}
+			out, err := elem.IsMediaFlowingOut(media, "")
+			if err != nil {
+				return false, err
+			}
+			if in || out {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,208 @@
+package reaper
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+// call is an invocation expected by a fake server, and its result.
+type call struct {
+	object, operation string
+	value             interface{}
+}
+
+// serve starts a fake server answering calls in order, and returns a
+// connection to it.
+func serve(t *testing.T, calls []call) (*kurento.Connection, *replay.Server) {
+	t.Helper()
+
+	var entries []replay.Entry
+	add := func(dir string, message map[string]interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, replay.Entry{Dir: dir, Message: data})
+	}
+	for i, c := range calls {
+		add(replay.Sent, map[string]interface{}{
+			"method": "invoke",
+			"params": map[string]interface{}{"object": c.object, "operation": c.operation},
+		})
+		add(replay.Received, map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      i + 1,
+			"result":  map[string]interface{}{"value": c.value},
+		})
+	}
+	match := func(expected, got map[string]interface{}) bool {
+		e, _ := expected["params"].(map[string]interface{})
+		g, _ := got["params"].(map[string]interface{})
+		return expected["method"] == got["method"] && e["object"] == g["object"] && e["operation"] == g["operation"]
+	}
+
+	srv := replay.NewServer(entries, replay.ServerOptions{Match: match})
+	ts := httptest.NewServer(srv)
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		ts.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ts.Close()
+	})
+	return conn, srv
+}
+
+const (
+	pipelineId = "P_kurento.MediaPipeline"
+	elementId  = pipelineId + "/E_kurento.WebRtcEndpoint"
+)
+
+func tags(owner string, age time.Duration) []map[string]string {
+	var ret []map[string]string
+	if owner != "" {
+		ret = append(ret, map[string]string{"key": OwnerTag, "value": owner})
+	}
+	heartbeat := strconv.FormatInt(time.Now().Add(-age).Unix(), 10)
+	return append(ret, map[string]string{"key": HeartbeatTag, "value": heartbeat})
+}
+
+// flows expects the queries of the media flow of a pipeline with one
+// element, where audio flows out if flowing.
+func flows(flowing bool) []call {
+	ret := []call{
+		{pipelineId, "getChildren", []string{elementId}},
+		{elementId, "isMediaFlowingIn", false},
+		{elementId, "isMediaFlowingOut", flowing},
+	}
+	if !flowing {
+		// video is queried too
+		ret = append(ret, call{elementId, "isMediaFlowingIn", false}, call{elementId, "isMediaFlowingOut", false})
+	}
+	return ret
+}
+
+func TestCheck(t *testing.T) {
+	options := Options{Owner: "me", HeartbeatInterval: time.Minute, StaleAfter: 3 * time.Minute, ClockSkew: time.Minute}
+	tests := []struct {
+		name     string
+		idle     time.Duration
+		owned    bool
+		calls    []call
+		want     Reason
+		released bool
+	}{
+		{
+			name:  "no owner",
+			calls: []call{{pipelineId, "getTags", tags("", time.Hour)}},
+		},
+		{
+			name:  "live heartbeat",
+			calls: []call{{pipelineId, "getTags", tags("other", time.Minute)}},
+		},
+		{
+			name:  "heartbeat within the clock skew",
+			calls: []call{{pipelineId, "getTags", tags("other", 3*time.Minute+30*time.Second)}},
+		},
+		{
+			name:     "stale heartbeat",
+			calls:    []call{{pipelineId, "getTags", tags("other", 5*time.Minute)}},
+			want:     ReasonStale,
+			released: true,
+		},
+		{
+			name:  "own pipeline",
+			owned: true,
+			calls: []call{{pipelineId, "getTags", tags("me", time.Hour)}},
+		},
+		{
+			// the owner of a previous run, e.g. in a restarted container
+			name:     "same owner, not created by the reaper",
+			calls:    []call{{pipelineId, "getTags", tags("me", 5*time.Minute)}},
+			want:     ReasonStale,
+			released: true,
+		},
+		{
+			name:  "first scan of an idle pipeline",
+			idle:  time.Nanosecond,
+			calls: append([]call{{pipelineId, "getTags", tags("me", 0)}}, flows(false)...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, srv := serve(t, tt.calls)
+			o := options
+			o.IdleAfter = tt.idle
+			r := New(conn, o)
+			if tt.owned {
+				r.owned[pipelineId] = true
+			}
+
+			pipeline := &kurento.MediaPipeline{}
+			kurento.HydrateMediaObject(pipelineId, nil, conn, pipeline)
+			c, err := r.check(pipeline)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := srv.Err(); err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case c == nil && tt.released:
+				t.Error("not released")
+			case c != nil && !tt.released:
+				t.Errorf("released as %s", c.Reason)
+			case c != nil && c.Reason != tt.want:
+				t.Errorf("reason = %s, want %s", c.Reason, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdle(t *testing.T) {
+	tests := []struct {
+		name    string
+		flowing []bool
+		want    []bool
+	}{
+		{"idle", []bool{false, false}, []bool{false, true}},
+		{"flowing", []bool{true, true}, []bool{false, false}},
+		{"stopped flowing", []bool{true, false, false}, []bool{false, true, true}},
+		{"flowing again", []bool{false, false, true, false}, []bool{false, true, false, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []call
+			for _, f := range tt.flowing {
+				calls = append(calls, flows(f)...)
+			}
+			conn, srv := serve(t, calls)
+			r := New(conn, Options{IdleAfter: time.Millisecond})
+
+			pipeline := &kurento.MediaPipeline{}
+			kurento.HydrateMediaObject(pipelineId, nil, conn, pipeline)
+			for i, want := range tt.want {
+				time.Sleep(2 * time.Millisecond)
+				idle, err := r.idle(pipeline)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if idle != want {
+					t.Errorf("scan %d: idle = %v, want %v", i, idle, want)
+				}
+			}
+			if err := srv.Err(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,396 @@
+package kurento
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MediaTypes returns the media types recorded with the profile.
+func (t MediaProfileSpecType) MediaTypes() []MediaType {
+	switch {
+	case strings.HasSuffix(string(t), "_AUDIO_ONLY"):
+		return []MediaType{MEDIATYPE_AUDIO}
+	case strings.HasSuffix(string(t), "_VIDEO_ONLY"):
+		return []MediaType{MEDIATYPE_VIDEO}
+	}
+	return []MediaType{MEDIATYPE_AUDIO, MEDIATYPE_VIDEO}
+}
+
+// CheckMediaProfile returns an error if profile records a media type which is
+// not in media. A recorder waits for every media of its profile before it
+// starts, so it would never start.
+func CheckMediaProfile(profile MediaProfileSpecType, media []MediaType) error {
+	for _, needed := range profile.MediaTypes() {
+		found := false
+		for _, m := range media {
+			found = found || m == needed
+		}
+		if !found {
+			return fmt.Errorf("kurento: profile %s records %s, which the source does not send", profile, needed)
+		}
+	}
+	return nil
+}
+
+// sentMedia returns the media types which source sends, or receives from
+// another element and may forward: an element which does not send a media
+// type yet, such as an endpoint which is not negotiated, has no connection
+// of this type either.
+func sentMedia(source IMediaElement) ([]MediaType, error) {
+	var media []MediaType
+	for _, m := range []MediaType{MEDIATYPE_AUDIO, MEDIATYPE_VIDEO} {
+		flowing, err := source.IsMediaFlowingOut(m, "")
+		if err != nil {
+			return nil, err
+		}
+		if !flowing {
+			connections, err := source.GetSourceConnections(m, "")
+			if err != nil {
+				return nil, err
+			}
+			flowing = len(connections) > 0
+		}
+		if flowing {
+			media = append(media, m)
+		}
+	}
+	return media, nil
+}
+
+// RecordingState is the state of a RecordingSession.
+type RecordingState int
+
+const (
+	// RecordingCreated is the state of a new session, before Start.
+	RecordingCreated RecordingState = iota
+	// RecordingStarting is the state after Start or Resume, until the
+	// recorder receives media.
+	RecordingStarting
+	// RecordingActive is the state while media is recorded.
+	RecordingActive
+	// RecordingPaused is the state after Pause.
+	RecordingPaused
+	// RecordingStopped is the state once the file is finalized.
+	RecordingStopped
+	// RecordingFailed is the state after an error event of the recorder.
+	RecordingFailed
+)
+
+func (s RecordingState) String() string {
+	switch s {
+	case RecordingCreated:
+		return "created"
+	case RecordingStarting:
+		return "starting"
+	case RecordingActive:
+		return "active"
+	case RecordingPaused:
+		return "paused"
+	case RecordingStopped:
+		return "stopped"
+	case RecordingFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("RecordingState(%d)", int(s))
+}
+
+// RecordingOptions configure a RecordingSession.
+type RecordingOptions struct {
+	// Uri where the recording is stored.
+	Uri string
+
+	// MediaProfile is the format of the recording, WEBM if empty.
+	MediaProfile MediaProfileSpecType
+
+	// SourceMedia are the media types which the source will send but does
+	// not send yet, such as those of an endpoint which is not negotiated.
+	// They are added to the media which the source sends or receives when
+	// the session is created, which must include every media type of
+	// MediaProfile.
+	SourceMedia []MediaType
+
+	// OnStateChange is called on every change of state. It is called from
+	// the event loop of the connection and must not make requests to KMS.
+	// Optional.
+	OnStateChange func(old, new RecordingState)
+
+	// OnError is called with the error events of the recorder, after the
+	// session entered RecordingFailed. Same restrictions as OnStateChange.
+	// Optional.
+	OnError func(ErrorEvent)
+}
+
+// RecordingSession records the media of an element with a RecorderEndpoint.
+// It follows the events of the recorder, and stops it with StopAndWait on
+// Close so that the file is always finalized before the recorder is
+// released.
+type RecordingSession struct {
+	Recorder *RecorderEndpoint
+
+	source   IMediaElement
+	media    []MediaType
+	options  RecordingOptions
+	handlers map[string]string
+
+	// transitions serializes Start, Pause, Resume and Close. It is not held
+	// by the event callbacks, which take lock only.
+	transitions sync.Mutex
+
+	lock    sync.Mutex
+	state   RecordingState
+	changed chan struct{}
+	err     error
+	closed  bool
+}
+
+// NewRecordingSession creates a recorder in the pipeline of source and
+// connects source to it. Recording begins with Start.
+func NewRecordingSession(source IMediaElement, options RecordingOptions) (*RecordingSession, error) {
+	if options.MediaProfile == "" {
+		options.MediaProfile = MEDIAPROFILESPECTYPE_WEBM
+	}
+	sourceMedia, err := sentMedia(source)
+	if err != nil {
+		return nil, err
+	}
+	sourceMedia = append(sourceMedia, options.SourceMedia...)
+	if err := CheckMediaProfile(options.MediaProfile, sourceMedia); err != nil {
+		return nil, err
+	}
+
+	s := &RecordingSession{
+		Recorder: &RecorderEndpoint{},
+		source:   source,
+		media:    options.MediaProfile.MediaTypes(),
+		options:  options,
+		handlers: make(map[string]string),
+		changed:  make(chan struct{}),
+	}
+	recorderOptions := RecorderEndpointOptions{Uri: options.Uri, MediaProfile: options.MediaProfile}
+	obj, ok := source.(IMediaObject)
+	if !ok {
+		return nil, fmt.Errorf("kurento: %T is not a remote object", source)
+	}
+	if err := pipelineOf(obj).Create(s.Recorder, recorderOptions.ConstructorParams()); err != nil {
+		return nil, err
+	}
+
+	if err := s.subscribe(); err != nil {
+		s.release()
+		return nil, err
+	}
+	for _, m := range s.media {
+		if err := source.Connect(s.Recorder, m, "", ""); err != nil {
+			s.release()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *RecordingSession) subscribe() error {
+	subscriptions := []struct {
+		event     string
+		subscribe func() (string, error)
+	}{
+		{"Recording", func() (string, error) {
+			return s.Recorder.SubscribeRecording(func(RecordingEvent) { s.eventState(RecordingActive) })
+		}},
+		{"Paused", func() (string, error) {
+			return s.Recorder.SubscribePaused(func(PausedEvent) { s.eventState(RecordingPaused) })
+		}},
+		{"Stopped", func() (string, error) {
+			return s.Recorder.SubscribeStopped(func(StoppedEvent) { s.eventState(RecordingStopped) })
+		}},
+		{"Error", func() (string, error) {
+			return s.Recorder.SubscribeError(s.onError)
+		}},
+	}
+	for _, sub := range subscriptions {
+		id, err := sub.subscribe()
+		if err != nil {
+			return err
+		}
+		s.handlers[sub.event] = id
+	}
+	return nil
+}
+
+// eventState applies a state reported by the recorder. A failed or stopped
+// session keeps its state.
+func (s *RecordingSession) eventState(state RecordingState) {
+	s.lock.Lock()
+	if s.state == RecordingFailed || s.state == RecordingStopped {
+		s.lock.Unlock()
+		return
+	}
+	old := s.setState(state)
+	s.lock.Unlock()
+	s.notify(old, state)
+}
+
+func (s *RecordingSession) onError(ev ErrorEvent) {
+	s.lock.Lock()
+	s.err = fmt.Errorf("[%d] %s", ev.ErrorCode, ev.Description)
+	old := s.setState(RecordingFailed)
+	s.lock.Unlock()
+
+	s.notify(old, RecordingFailed)
+	if s.options.OnError != nil {
+		s.options.OnError(ev)
+	}
+}
+
+// setState must be called with the lock held. It returns the previous state.
+func (s *RecordingSession) setState(state RecordingState) RecordingState {
+	old := s.state
+	if old != state {
+		s.state = state
+		close(s.changed)
+		s.changed = make(chan struct{})
+	}
+	return old
+}
+
+func (s *RecordingSession) notify(old, new RecordingState) {
+	if old != new && s.options.OnStateChange != nil {
+		s.options.OnStateChange(old, new)
+	}
+}
+
+// transition calls fn if the session is in one of the states from, and
+// then moves it to state to. Transitions run one at a time, so that two of
+// them cannot both start from the same state.
+func (s *RecordingSession) transition(action string, to RecordingState, fn func() error, from ...RecordingState) error {
+	s.transitions.Lock()
+	defer s.transitions.Unlock()
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return fmt.Errorf("kurento: cannot %s a closed recording", action)
+	}
+	current := s.state
+	allowed := false
+	for _, f := range from {
+		allowed = allowed || f == current
+	}
+	s.lock.Unlock()
+	if !allowed {
+		return fmt.Errorf("kurento: cannot %s a recording in state %s", action, current)
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	old := current
+	// an event may have moved the session further already
+	if s.state == current {
+		old = s.setState(to)
+	} else {
+		to = old
+	}
+	s.lock.Unlock()
+	s.notify(old, to)
+	return nil
+}
+
+// Start starts recording. The session is RecordingActive once media reaches
+// the recorder.
+func (s *RecordingSession) Start() error {
+	return s.transition("start", RecordingStarting, s.Recorder.Record, RecordingCreated)
+}
+
+// Pause pauses the recording.
+func (s *RecordingSession) Pause() error {
+	return s.transition("pause", RecordingPaused, s.Recorder.Pause, RecordingStarting, RecordingActive)
+}
+
+// Resume resumes a paused recording.
+func (s *RecordingSession) Resume() error {
+	return s.transition("resume", RecordingStarting, s.Recorder.Record, RecordingPaused)
+}
+
+// State returns the current state, and the error which made the session
+// fail in RecordingFailed.
+func (s *RecordingSession) State() (RecordingState, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.state, s.err
+}
+
+// WaitState waits until the session is in one of the given states, and
+// returns it.
+func (s *RecordingSession) WaitState(ctx context.Context, states ...RecordingState) (RecordingState, error) {
+	for {
+		s.lock.Lock()
+		current, changed := s.state, s.changed
+		s.lock.Unlock()
+
+		for _, state := range states {
+			if state == current {
+				return current, nil
+			}
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return current, ctx.Err()
+		}
+	}
+}
+
+// Close stops the recording with StopAndWait, which returns once the file
+// is finalized, and releases the recorder. Close must not be called from an
+// event callback.
+func (s *RecordingSession) Close() error {
+	s.transitions.Lock()
+	defer s.transitions.Unlock()
+
+	s.lock.Lock()
+	if s.closed {
+		s.lock.Unlock()
+		return nil
+	}
+	s.closed = true
+	state := s.state
+	s.lock.Unlock()
+
+	var ret error
+	switch state {
+	case RecordingStarting, RecordingActive, RecordingPaused:
+		if err := s.Recorder.StopAndWait(); err != nil {
+			ret = err
+		} else {
+			s.eventState(RecordingStopped)
+		}
+	}
+	for _, m := range s.media {
+		if err := s.source.Disconnect(s.Recorder, m, "", ""); err != nil {
+			ret = errors.Join(ret, err)
+		}
+	}
+	if err := s.release(); err != nil {
+		ret = errors.Join(ret, err)
+	}
+	return ret
+}
+
+func (s *RecordingSession) release() error {
+	var ret error
+	for event, id := range s.handlers {
+		if err := s.Recorder.Unsubscribe(event, id); err != nil {
+			ret = errors.Join(ret, err)
+		}
+	}
+	if err := s.Recorder.Release(); err != nil {
+		ret = errors.Join(ret, err)
+	}
+	return ret
+}
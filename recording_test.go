@@ -0,0 +1,234 @@
+package kurento_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// sourceMedia expects the queries of the media sent by "pipe/src": flowing
+// media, then the source connections of the media which does not flow.
+func (s *script) sourceMedia(flowing, connected []string) {
+	for _, m := range []string{"AUDIO", "VIDEO"} {
+		isFlowing := contains(flowing, m)
+		s.call("invoke", map[string]interface{}{
+			"object":          "pipe/src",
+			"operation":       "isMediaFlowingOut",
+			"operationParams": map[string]interface{}{"mediaType": m},
+		}, isFlowing)
+		if isFlowing {
+			continue
+		}
+		var connections []interface{}
+		if contains(connected, m) {
+			connections = append(connections, map[string]interface{}{
+				"source": "pipe/other", "sink": "pipe/src", "type": m,
+			})
+		}
+		s.call("invoke", map[string]interface{}{
+			"object":          "pipe/src",
+			"operation":       "getSourceConnections",
+			"operationParams": map[string]interface{}{"mediaType": m},
+		}, connections)
+	}
+}
+
+// recorder expects the creation of the recorder "pipe/rec", its
+// subscriptions and its connection to the source for media.
+func (s *script) recorder(media ...string) {
+	s.recorderOf("pipe/rec", media...)
+}
+
+// recorderOf is recorder for the recorder of the given ID.
+func (s *script) recorderOf(id string, media ...string) {
+	s.create("RecorderEndpoint", id)
+	for _, event := range []string{"Recording", "Paused", "Stopped", "Error"} {
+		s.subscribe(id, event, id+"/"+event)
+	}
+	for _, m := range media {
+		s.call("invoke", map[string]interface{}{
+			"object":          "pipe/src",
+			"operation":       "connect",
+			"operationParams": map[string]interface{}{"sink": id, "mediaType": m},
+		}, nil)
+	}
+}
+
+// closeRecorder expects the end of a recording session which was started.
+func (s *script) closeRecorder(media ...string) {
+	s.closeRecorderOf("pipe/rec", media...)
+}
+
+// closeRecorderOf is closeRecorder for the recorder of the given ID.
+func (s *script) closeRecorderOf(id string, media ...string) {
+	s.invoke(id, "stopAndWait", nil)
+	for _, m := range media {
+		s.call("invoke", map[string]interface{}{
+			"object":          "pipe/src",
+			"operation":       "disconnect",
+			"operationParams": map[string]interface{}{"sink": id, "mediaType": m},
+		}, nil)
+	}
+	for range 4 {
+		s.unsubscribe(id)
+	}
+	s.release(id)
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newSource(conn *kurento.Connection) *kurento.WebRtcEndpoint {
+	src := &kurento.WebRtcEndpoint{}
+	kurento.HydrateMediaObject("pipe/src", nil, conn, src)
+	return src
+}
+
+func TestRecordingSessionMedia(t *testing.T) {
+	tests := []struct {
+		name      string
+		profile   kurento.MediaProfileSpecType
+		flowing   []string
+		connected []string
+		declared  []kurento.MediaType
+		wantErr   bool
+	}{
+		{name: "flowing", flowing: []string{"AUDIO", "VIDEO"}},
+		{name: "connected", flowing: []string{"AUDIO"}, connected: []string{"VIDEO"}},
+		{name: "video missing", flowing: []string{"AUDIO"}, wantErr: true},
+		{name: "nothing sent yet", wantErr: true},
+		{name: "declared", flowing: []string{"AUDIO"}, declared: []kurento.MediaType{kurento.MEDIATYPE_VIDEO}},
+		{name: "audio profile", profile: kurento.MEDIAPROFILESPECTYPE_WEBM_AUDIO_ONLY, flowing: []string{"AUDIO"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			profile := tt.profile
+			if profile == "" {
+				profile = kurento.MEDIAPROFILESPECTYPE_WEBM
+			}
+			var media []string
+			for _, m := range profile.MediaTypes() {
+				media = append(media, string(m))
+			}
+
+			s := &script{}
+			s.sourceMedia(tt.flowing, tt.connected)
+			if !tt.wantErr {
+				s.recorder(media...)
+			}
+			conn, srv := s.serve(t)
+
+			_, err := kurento.NewRecordingSession(newSource(conn), kurento.RecordingOptions{
+				Uri:          "file:///tmp/rec.webm",
+				MediaProfile: tt.profile,
+				SourceMedia:  tt.declared,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+			done(t, srv)
+		})
+	}
+}
+
+func TestRecordingSessionTransitions(t *testing.T) {
+	s := &script{}
+	s.sourceMedia([]string{"AUDIO", "VIDEO"}, nil)
+	s.recorder("AUDIO", "VIDEO")
+	s.invoke("pipe/rec", "record", nil)
+	// after the session moved to RecordingStarting
+	s.delay(50 * time.Millisecond)
+	s.event("pipe/rec", "Recording", nil)
+	s.invoke("pipe/rec", "pause", nil)
+	s.invoke("pipe/rec", "record", nil)
+	s.closeRecorder("AUDIO", "VIDEO")
+	conn, srv := s.serve(t)
+
+	var lock sync.Mutex
+	var changes []string
+	session, err := kurento.NewRecordingSession(newSource(conn), kurento.RecordingOptions{
+		Uri: "file:///tmp/rec.webm",
+		OnStateChange: func(old, new kurento.RecordingState) {
+			lock.Lock()
+			defer lock.Unlock()
+			changes = append(changes, fmt.Sprintf("%s>%s", old, new))
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	steps := []struct {
+		name    string
+		action  func() error
+		wantErr bool
+		want    kurento.RecordingState
+	}{
+		{"pause before start", session.Pause, true, kurento.RecordingCreated},
+		{"start", session.Start, false, kurento.RecordingActive},
+		{"start again", session.Start, true, kurento.RecordingActive},
+		{"pause", session.Pause, false, kurento.RecordingPaused},
+		{"resume", session.Resume, false, kurento.RecordingStarting},
+		{"close", session.Close, false, kurento.RecordingStopped},
+		{"start after close", session.Start, true, kurento.RecordingStopped},
+	}
+	for _, step := range steps {
+		if err := step.action(); (err != nil) != step.wantErr {
+			t.Fatalf("%s: err = %v, want error %v", step.name, err, step.wantErr)
+		}
+		if state, err := session.WaitState(ctx, step.want); err != nil {
+			t.Fatalf("%s: state %s, want %s", step.name, state, step.want)
+		}
+	}
+	done(t, srv)
+
+	lock.Lock()
+	defer lock.Unlock()
+	want := "[created>starting starting>active active>paused paused>starting starting>stopped]"
+	if fmt.Sprint(changes) != want {
+		t.Errorf("changes = %v, want %s", changes, want)
+	}
+}
+
+// TestRecordingSessionConcurrentStart checks that only one of concurrent
+// calls of Start records: the script has a single record request.
+func TestRecordingSessionConcurrentStart(t *testing.T) {
+	s := &script{}
+	s.sourceMedia([]string{"AUDIO", "VIDEO"}, nil)
+	s.recorder("AUDIO", "VIDEO")
+	s.invoke("pipe/rec", "record", nil)
+	conn, srv := s.serve(t)
+
+	session, err := kurento.NewRecordingSession(newSource(conn), kurento.RecordingOptions{Uri: "file:///tmp/rec.webm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const callers = 4
+	errs := make(chan error, callers)
+	for range callers {
+		go func() { errs <- session.Start() }()
+	}
+	failed := 0
+	for range callers {
+		if err := wait(t, errs); err != nil {
+			failed++
+		}
+	}
+	if failed != callers-1 {
+		t.Errorf("%d calls failed, want %d", failed, callers-1)
+	}
+	done(t, srv)
+}
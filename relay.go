@@ -0,0 +1,143 @@
+package kurento
+
+import (
+	"errors"
+	"sync"
+)
+
+var ErrRelayReleased = errors.New("kurento: relay has been released")
+
+// RelayOptions configure a Relay.
+type RelayOptions struct {
+	// Audio and Video select the media relayed. Both are relayed if both
+	// are false.
+	Audio, Video bool
+
+	// Crypto encrypts the relay with SRTP, given to the endpoints on both
+	// servers. See NewSDES. Optional.
+	Crypto *SDES
+
+	// Use IPv6 between the servers instead of IPv4
+	UseIpv6 bool
+}
+
+// Relay cascades the media of an element to a pipeline of another server,
+// through a pair of RtpEndpoints. The media arrives in Sink, which fans it
+// out to every element connected with Connect, so a broadcast can span
+// several servers.
+type Relay struct {
+	// Source is the endpoint sending the media, on the server of the
+	// source element.
+	Source *RtpEndpoint
+	// Sink is the endpoint receiving the media, in the target pipeline.
+	Sink *RtpEndpoint
+
+	lock     sync.Mutex
+	sinks    []IMediaElement
+	released bool
+}
+
+// NewRelay creates the endpoints of a relay from source, an element of
+// sourcePipeline, to target, usually a pipeline of another Connection.
+// The sink endpoint answers the offer of the source endpoint, and the
+// source element is connected to it once negotiated.
+func NewRelay(sourcePipeline *MediaPipeline, source IMediaElement, target *MediaPipeline, options RelayOptions) (*Relay, error) {
+	audio, video := options.Audio, options.Video
+	if !audio && !video {
+		audio, video = true, true
+	}
+	endpointOptions := RtpEndpointOptions{Crypto: options.Crypto, UseIpv6: options.UseIpv6}
+
+	r := &Relay{}
+	var err error
+	if r.Source, err = NewRtpEndpoint(sourcePipeline, endpointOptions); err != nil {
+		return nil, err
+	}
+	if r.Sink, err = NewRtpEndpoint(target, endpointOptions); err != nil {
+		r.Source.Release()
+		return nil, err
+	}
+	if err = r.negotiate(source, audio, video); err != nil {
+		r.Release()
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Relay) negotiate(source IMediaElement, audio, video bool) error {
+	offer, err := r.Source.GenerateRtpOffer(audio, video)
+	if err != nil {
+		return err
+	}
+	answer, err := r.Sink.ProcessOffer(offer)
+	if err != nil {
+		return err
+	}
+	if _, err := r.Source.ProcessAnswer(answer); err != nil {
+		return err
+	}
+
+	if audio && video {
+		return source.Connect(r.Source, "", "", "")
+	}
+	media := MEDIATYPE_AUDIO
+	if video {
+		media = MEDIATYPE_VIDEO
+	}
+	return source.Connect(r.Source, media, "", "")
+}
+
+// Connect connects the sink endpoint to sink, an element of the target
+// pipeline.
+func (r *Relay) Connect(sink IMediaElement) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.released {
+		return ErrRelayReleased
+	}
+	if err := r.Sink.Connect(sink, "", "", ""); err != nil {
+		return err
+	}
+	r.sinks = append(r.sinks, sink)
+	return nil
+}
+
+// Disconnect disconnects sink from the sink endpoint.
+func (r *Relay) Disconnect(sink IMediaElement) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.released {
+		return ErrRelayReleased
+	}
+	for i, s := range r.sinks {
+		if s == sink {
+			r.sinks = append(r.sinks[:i], r.sinks[i+1:]...)
+			break
+		}
+	}
+	return r.Sink.Disconnect(sink, "", "", "")
+}
+
+// Sinks returns the elements connected to the sink endpoint.
+func (r *Relay) Sinks() []IMediaElement {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]IMediaElement(nil), r.sinks...)
+}
+
+// Release releases the endpoints on both servers. The elements connected
+// to the relay are left untouched.
+func (r *Relay) Release() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.released {
+		return nil
+	}
+	r.released = true
+	r.sinks = nil
+	return errors.Join(r.Source.Release(), r.Sink.Release())
+}
@@ -0,0 +1,121 @@
+package kurento_test
+
+import (
+	"errors"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestRelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		options kurento.RelayOptions
+		// offer are the media offered, connect the media connected to the
+		// source endpoint
+		offer   map[string]interface{}
+		connect map[string]interface{}
+		fail    string
+	}{
+		{
+			name:    "audio and video",
+			offer:   map[string]interface{}{"offerToReceiveAudio": true, "offerToReceiveVideo": true},
+			connect: map[string]interface{}{"sink": "a/src"},
+		},
+		{
+			name:    "audio",
+			options: kurento.RelayOptions{Audio: true},
+			offer:   map[string]interface{}{"offerToReceiveAudio": true, "offerToReceiveVideo": false},
+			connect: map[string]interface{}{"sink": "a/src", "mediaType": "AUDIO"},
+		},
+		{
+			name:    "video",
+			options: kurento.RelayOptions{Video: true},
+			offer:   map[string]interface{}{"offerToReceiveAudio": false, "offerToReceiveVideo": true},
+			connect: map[string]interface{}{"sink": "a/src", "mediaType": "VIDEO"},
+		},
+		{
+			name:  "offer rejected",
+			offer: map[string]interface{}{"offerToReceiveAudio": true, "offerToReceiveVideo": true},
+			fail:  "processOffer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &script{}
+			s.call("create", map[string]interface{}{
+				"type":              "RtpEndpoint",
+				"constructorParams": map[string]interface{}{"mediaPipeline": "a"},
+			}, "a/src")
+			s.call("create", map[string]interface{}{
+				"type":              "RtpEndpoint",
+				"constructorParams": map[string]interface{}{"mediaPipeline": "b"},
+			}, "b/sink")
+			s.call("invoke", map[string]interface{}{
+				"object":          "a/src",
+				"operation":       "generateOffer",
+				"operationParams": map[string]interface{}{"options": tt.offer},
+			}, "offer")
+			if tt.fail != "" {
+				s.fail("invoke", map[string]interface{}{"object": "b/sink", "operation": tt.fail}, 40101, "failed")
+				s.release("a/src")
+				s.release("b/sink")
+			} else {
+				s.invoke("b/sink", "processOffer", "answer")
+				s.invoke("a/src", "processAnswer", "")
+				s.call("invoke", map[string]interface{}{
+					"object":          "a/player",
+					"operation":       "connect",
+					"operationParams": tt.connect,
+				}, nil)
+				s.call("invoke", map[string]interface{}{
+					"object":          "b/sink",
+					"operation":       "connect",
+					"operationParams": map[string]interface{}{"sink": "b/viewer"},
+				}, nil)
+				s.release("a/src")
+				s.release("b/sink")
+			}
+			conn, srv := s.serve(t)
+
+			source, target := &kurento.MediaPipeline{}, &kurento.MediaPipeline{}
+			player, viewer := &kurento.PlayerEndpoint{}, &kurento.WebRtcEndpoint{}
+			kurento.HydrateMediaObject("a", nil, conn, source)
+			kurento.HydrateMediaObject("b", nil, conn, target)
+			kurento.HydrateMediaObject("a/player", nil, conn, player)
+			kurento.HydrateMediaObject("b/viewer", nil, conn, viewer)
+
+			relay, err := kurento.NewRelay(source, player, target, tt.options)
+			if tt.fail != "" {
+				if err == nil {
+					t.Fatal("relay negotiated")
+				}
+				done(t, srv)
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := relay.Connect(viewer); err != nil {
+				t.Fatal(err)
+			}
+			if sinks := relay.Sinks(); len(sinks) != 1 || sinks[0] != kurento.IMediaElement(viewer) {
+				t.Errorf("sinks = %v", sinks)
+			}
+			if err := relay.Release(); err != nil {
+				t.Fatal(err)
+			}
+			// released once
+			if err := relay.Release(); err != nil {
+				t.Fatal(err)
+			}
+			if err := relay.Connect(viewer); !errors.Is(err, kurento.ErrRelayReleased) {
+				t.Errorf("Connect() after Release = %v", err)
+			}
+			if len(relay.Sinks()) != 0 {
+				t.Error("sinks kept after Release")
+			}
+			done(t, srv)
+		})
+	}
+}
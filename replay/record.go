@@ -0,0 +1,161 @@
+// Package replay records the JSON-RPC traffic of a Kurento connection to a
+// JSONL transcript, and replays it with a fake server, so the signaling
+// logic of an application can be tested without KMS.
+//
+// A transcript is recorded from a session with a real server:
+//
+//	rec := replay.NewRecorder(file)
+//	rec.Attach(conn)
+//
+// and replayed in a test:
+//
+//	transcript, err := replay.Load(file)
+//	srv := httptest.NewServer(replay.NewServer(transcript, replay.ServerOptions{}))
+//	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(srv.URL, "http"))
+//
+// The transcripts are normalized: request IDs are numbered from 1, and the
+// UUIDs of the objects and sessions are replaced by sequential ones, in
+// order of appearance.
+package replay
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// Directions of the messages of a transcript.
+const (
+	Sent     = "sent"
+	Received = "received"
+)
+
+// Entry is a message of a transcript.
+type Entry struct {
+	// Elapsed is the time since the start of the recording, in milliseconds.
+	Elapsed int64 `json:"elapsed"`
+	// Dir is Sent for the messages sent to KMS, Received for the others.
+	Dir     string          `json:"dir"`
+	Message json.RawMessage `json:"message"`
+}
+
+// Load reads a transcript written by a Recorder.
+func Load(r io.Reader) ([]Entry, error) {
+	var ret []Entry
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("replay: line %d: %w", line, err)
+		}
+		if entry.Dir != Sent && entry.Dir != Received {
+			return nil, fmt.Errorf("replay: line %d: invalid direction %q", line, entry.Dir)
+		}
+		ret = append(ret, entry)
+	}
+	return ret, scanner.Err()
+}
+
+var uuidPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// Recorder writes the messages of a connection to a transcript, one entry
+// per line.
+type Recorder struct {
+	lock  sync.Mutex
+	enc   *json.Encoder
+	start time.Time
+	err   error
+
+	// normalized request IDs, by ID of the connection
+	ids    map[int64]int64
+	nextId int64
+	uuids  map[string]string
+}
+
+// NewRecorder returns a recorder writing to w.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		enc:   json.NewEncoder(w),
+		start: time.Now(),
+		ids:   make(map[int64]int64),
+		uuids: make(map[string]string),
+	}
+}
+
+// Attach records the messages of c, from now on. It replaces the wire tap
+// of c.
+func (r *Recorder) Attach(c *kurento.Connection) {
+	c.SetWireTap(r.Record)
+}
+
+// Record adds a message to the transcript.
+func (r *Recorder) Record(sent bool, message []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.err != nil {
+		return
+	}
+	entry := Entry{Elapsed: time.Since(r.start).Milliseconds(), Dir: Received}
+	if sent {
+		entry.Dir = Sent
+	}
+	entry.Message, r.err = r.normalize(sent, message)
+	if r.err == nil {
+		r.err = r.enc.Encode(entry)
+	}
+}
+
+// Err returns the first error of the recording, after which nothing is
+// recorded.
+func (r *Recorder) Err() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return r.err
+}
+
+// normalize renumbers the request ID of message, and replaces its UUIDs.
+// It must be called with the lock held.
+func (r *Recorder) normalize(sent bool, message []byte) (json.RawMessage, error) {
+	message = uuidPattern.ReplaceAllFunc(message, func(uuid []byte) []byte {
+		normalized, ok := r.uuids[string(uuid)]
+		if !ok {
+			normalized = fmt.Sprintf("00000000-0000-0000-0000-%012d", len(r.uuids)+1)
+			r.uuids[string(uuid)] = normalized
+		}
+		return []byte(normalized)
+	})
+
+	var msg map[string]interface{}
+	if err := json.Unmarshal(message, &msg); err != nil {
+		return nil, err
+	}
+	id, ok := msg["id"].(float64)
+	if !ok {
+		return message, nil
+	}
+	normalized, known := r.ids[int64(id)]
+	if sent {
+		r.nextId++
+		normalized = r.nextId
+		r.ids[int64(id)] = normalized
+	} else if known {
+		// a request is answered once
+		delete(r.ids, int64(id))
+	} else {
+		return message, nil
+	}
+	msg["id"] = normalized
+	return json.Marshal(msg)
+}
@@ -0,0 +1,90 @@
+package replay
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name       string
+		transcript string
+		want       int
+		fails      bool
+	}{
+		{"empty", "", 0, false},
+		{"entries", `{"elapsed":0,"dir":"sent","message":{"id":1}}` + "\n\n" + `{"elapsed":3,"dir":"received","message":{"id":1}}` + "\n", 2, false},
+		{"invalid entry", "{\n", 0, true},
+		{"invalid direction", `{"dir":"up","message":{}}`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Load(strings.NewReader(tt.transcript))
+			if (err != nil) != tt.fails {
+				t.Fatalf("Load() = %v", err)
+			}
+			if len(entries) != tt.want {
+				t.Errorf("%d entries, want %d", len(entries), tt.want)
+			}
+		})
+	}
+}
+
+func TestRecord(t *testing.T) {
+	const (
+		pipeline = "5c0e1fa4-9b7c-4b3e-a1a8-2d5a50c4e0a1_kurento.MediaPipeline"
+		session  = "0f6a3b3e-1c1d-4a59-8f5e-d3ebf0a3f1c2"
+	)
+	messages := []struct {
+		sent    bool
+		message string
+	}{
+		{true, `{"id":7,"method":"create","params":{"type":"MediaPipeline"}}`},
+		{true, `{"id":9,"method":"ping"}`},
+		{false, `{"id":9,"result":{"value":"pong"}}`},
+		{false, `{"id":7,"result":{"value":"` + pipeline + `","sessionId":"` + session + `"}}`},
+		// answered once
+		{false, `{"id":7,"result":{}}`},
+		{false, `{"method":"onEvent","params":{"value":{"object":"` + pipeline + `"}}}`},
+		{true, `{"id":10,"method":"release","params":{"object":"` + pipeline + `"}}`},
+	}
+	want := []string{
+		`{"id":1,"method":"create","params":{"type":"MediaPipeline"}}`,
+		`{"id":2,"method":"ping"}`,
+		`{"id":2,"result":{"value":"pong"}}`,
+		`{"id":1,"result":{"sessionId":"00000000-0000-0000-0000-000000000002","value":"00000000-0000-0000-0000-000000000001_kurento.MediaPipeline"}}`,
+		`{"id":7,"result":{}}`,
+		`{"method":"onEvent","params":{"value":{"object":"00000000-0000-0000-0000-000000000001_kurento.MediaPipeline"}}}`,
+		`{"id":3,"method":"release","params":{"object":"00000000-0000-0000-0000-000000000001_kurento.MediaPipeline"}}`,
+	}
+
+	var buf bytes.Buffer
+	rec := NewRecorder(&buf)
+	for _, m := range messages {
+		rec.Record(m.sent, []byte(m.message))
+	}
+	if err := rec.Err(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Load(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(want) {
+		t.Fatalf("%d entries, want %d", len(entries), len(want))
+	}
+	for i, entry := range entries {
+		if dir := map[bool]string{true: Sent, false: Received}[messages[i].sent]; entry.Dir != dir {
+			t.Errorf("entry %d: direction %s, want %s", i, entry.Dir, dir)
+		}
+		if string(entry.Message) != want[i] {
+			t.Errorf("entry %d: %s, want %s", i, entry.Message, want[i])
+		}
+	}
+
+	rec.Record(true, []byte("not json"))
+	if rec.Err() == nil {
+		t.Error("invalid message recorded")
+	}
+}
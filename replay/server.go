@@ -0,0 +1,191 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ErrUnexpectedRequest is the error of a request which does not match the
+// transcript.
+var ErrUnexpectedRequest = errors.New("replay: unexpected request")
+
+// ServerOptions configure a Server.
+type ServerOptions struct {
+	// RealTime sends the received messages with the delays of the
+	// recording, instead of at once.
+	RealTime bool
+
+	// Match tells if a request matches the one expected by the transcript.
+	// Both are decoded, without their ID. By default, they must be equal.
+	// Optional.
+	Match func(expected, got map[string]interface{}) bool
+}
+
+// Server is a fake KMS replaying a transcript. It expects the requests of
+// the transcript in order, and answers each with the responses and events
+// recorded after it, whose request IDs are those of the client. Requests
+// made concurrently can reach it in another order than recorded, so the
+// replayed code must make them one at a time.
+type Server struct {
+	entries []Entry
+	options ServerOptions
+
+	lock sync.Mutex
+	pos  int
+	err  error
+	done chan struct{}
+}
+
+// NewServer returns a server replaying entries. It handles the websocket
+// connections of kurento.NewConnection, on any path.
+func NewServer(entries []Entry, options ServerOptions) *Server {
+	if options.Match == nil {
+		options.Match = func(expected, got map[string]interface{}) bool {
+			return reflect.DeepEqual(expected, got)
+		}
+	}
+	return &Server{entries: entries, options: options, done: make(chan struct{})}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	websocket.Handler(s.serve).ServeHTTP(w, r)
+}
+
+// Done is closed once the whole transcript is replayed.
+func (s *Server) Done() <-chan struct{} {
+	return s.done
+}
+
+// Err returns the first mismatch between the requests and the transcript.
+func (s *Server) Err() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.err
+}
+
+func (s *Server) fail(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.err == nil {
+		s.err = err
+	}
+}
+
+func (s *Server) serve(ws *websocket.Conn) {
+	// IDs of the client, by ID in the transcript
+	ids := make(map[int64]int64)
+	for {
+		if err := s.sendReceived(ws, ids); err != nil {
+			return
+		}
+
+		var message string
+		if err := websocket.Message.Receive(ws, &message); err != nil {
+			return
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal([]byte(message), &got); err != nil {
+			s.fail(fmt.Errorf("replay: invalid request: %w", err))
+			return
+		}
+		id, _ := got["id"].(float64)
+		delete(got, "id")
+
+		s.lock.Lock()
+		var expected map[string]interface{}
+		var expectedId float64
+		pos := s.pos
+		if pos < len(s.entries) {
+			if err := json.Unmarshal(s.entries[pos].Message, &expected); err != nil {
+				s.lock.Unlock()
+				s.fail(fmt.Errorf("replay: entry %d: %w", pos, err))
+				return
+			}
+			expectedId, _ = expected["id"].(float64)
+			delete(expected, "id")
+		}
+		s.lock.Unlock()
+
+		if expected == nil || !s.options.Match(expected, got) {
+			err := fmt.Errorf("%w at entry %d: %s", ErrUnexpectedRequest, pos, message)
+			s.fail(err)
+			websocket.JSON.Send(ws, map[string]interface{}{
+				"jsonrpc": "2.0",
+				"id":      int64(id),
+				"error":   map[string]interface{}{"code": -32600, "message": err.Error()},
+			})
+			continue
+		}
+		ids[int64(expectedId)] = int64(id)
+
+		s.lock.Lock()
+		s.pos++
+		s.lock.Unlock()
+	}
+}
+
+// sendReceived sends the received messages following the position in the
+// transcript, up to the next request.
+func (s *Server) sendReceived(ws *websocket.Conn, ids map[int64]int64) error {
+	for {
+		s.lock.Lock()
+		if s.pos >= len(s.entries) {
+			s.lock.Unlock()
+			s.finish()
+			return nil
+		}
+		entry := s.entries[s.pos]
+		var previous int64
+		if s.pos > 0 {
+			previous = s.entries[s.pos-1].Elapsed
+		}
+		s.lock.Unlock()
+
+		if entry.Dir != Received {
+			return nil
+		}
+		if s.options.RealTime && entry.Elapsed > previous {
+			time.Sleep(time.Duration(entry.Elapsed-previous) * time.Millisecond)
+		}
+
+		var msg map[string]interface{}
+		if err := json.Unmarshal(entry.Message, &msg); err != nil {
+			s.fail(fmt.Errorf("replay: entry %d: %w", s.pos, err))
+			return err
+		}
+		if id, ok := msg["id"].(float64); ok {
+			if clientId, ok := ids[int64(id)]; ok {
+				msg["id"] = clientId
+				delete(ids, int64(id))
+			}
+		}
+		if err := websocket.JSON.Send(ws, msg); err != nil {
+			return err
+		}
+
+		s.lock.Lock()
+		s.pos++
+		s.lock.Unlock()
+	}
+}
+
+func (s *Server) finish() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	select {
+	case <-s.done:
+	default:
+		close(s.done)
+	}
+}
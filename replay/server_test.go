@@ -0,0 +1,78 @@
+package replay
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// transcript creates a pipeline and gets its name.
+const transcript = `{"elapsed":0,"dir":"sent","message":{"id":1,"jsonrpc":"2.0","method":"create","params":{"type":"MediaPipeline","constructorParams":null}}}
+{"elapsed":2,"dir":"received","message":{"id":1,"jsonrpc":"2.0","result":{"value":"p1","sessionId":"s1"}}}
+{"elapsed":4,"dir":"sent","message":{"id":2,"jsonrpc":"2.0","method":"invoke","params":{"object":"p1","operation":"getName","sessionId":"s1"},"sessionId":"s1"}}
+{"elapsed":6,"dir":"received","message":{"id":2,"jsonrpc":"2.0","result":{"value":"pipeline"}}}
+`
+
+func TestServer(t *testing.T) {
+	tests := []struct {
+		name  string
+		match func(expected, got map[string]interface{}) bool
+		want  error
+	}{
+		{"default match", nil, nil},
+		{"operation", func(expected, got map[string]interface{}) bool {
+			e, _ := expected["params"].(map[string]interface{})
+			g, _ := got["params"].(map[string]interface{})
+			return expected["method"] == got["method"] && e["operation"] == g["operation"]
+		}, nil},
+		{"mismatch", func(expected, got map[string]interface{}) bool {
+			return expected["method"] == "create"
+		}, ErrUnexpectedRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := Load(strings.NewReader(transcript))
+			if err != nil {
+				t.Fatal(err)
+			}
+			srv := NewServer(entries, ServerOptions{Match: tt.match, RealTime: true})
+			ts := httptest.NewServer(srv)
+			defer ts.Close()
+			conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer conn.Close()
+
+			pipeline := &kurento.MediaPipeline{}
+			if err := conn.Create(pipeline, nil); err != nil {
+				t.Fatal(err)
+			}
+			if pipeline.Id != "p1" {
+				t.Errorf("pipeline %s, want p1", pipeline.Id)
+			}
+			name, err := pipeline.GetName()
+			if tt.want != nil {
+				if err == nil || !errors.Is(srv.Err(), tt.want) {
+					t.Errorf("GetName() = %q, %v, server error %v", name, err, srv.Err())
+				}
+				return
+			}
+			if err != nil || name != "pipeline" {
+				t.Fatalf("GetName() = %q, %v", name, err)
+			}
+			select {
+			case <-srv.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("transcript not replayed")
+			}
+			if err := srv.Err(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package kurento
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// RoomOptions configure a Room.
+type RoomOptions struct {
+	// MaxPublishers is the maximum number of publishers, 0 for no limit.
+	MaxPublishers int
+
+	// OnIceCandidate sends a local candidate to a participant. subscriber is
+	// empty for the endpoint publishing the media of publisher, else it is
+	// the participant receiving that media. Required.
+	OnIceCandidate func(publisher, subscriber string, candidate IceCandidate) error
+
+	// OnIceGatheringDone tells a participant that all the candidates of an
+	// endpoint were sent. Optional.
+	OnIceGatheringDone func(publisher, subscriber string) error
+}
+
+// subscription is the endpoint sending the media of a publisher to a
+// subscriber.
+type subscription struct {
+	endpoint *WebRtcEndpoint
+	trickle  *TrickleSession
+
+	// lock serializes the changes of muted, and is held while they are
+	// applied
+	lock  sync.Mutex
+	muted map[MediaType]bool
+}
+
+func (s *subscription) release() error {
+	s.trickle.Close()
+	return s.endpoint.Release()
+}
+
+type roomPublisher struct {
+	endpoint *WebRtcEndpoint
+	trickle  *TrickleSession
+
+	// subscribers are the subscriptions to the publisher, by subscriber
+	subscribers map[string]*subscription
+}
+
+// Room forwards the media of each publisher to its subscribers without
+// mixing: every subscription has its own endpoint, connected to the
+// endpoint of the publisher. A participant can publish and subscribe to
+// any number of other publishers.
+type Room struct {
+	Pipeline *MediaPipeline
+
+	options    RoomOptions
+	lock       sync.Mutex
+	publishers map[string]*roomPublisher
+	released   bool
+}
+
+// NewRoom creates the pipeline of a room on c.
+func NewRoom(c *Connection, options RoomOptions) (*Room, error) {
+	if options.OnIceCandidate == nil {
+		return nil, errMissingOnIce
+	}
+	r := &Room{
+		Pipeline:   &MediaPipeline{},
+		options:    options,
+		publishers: make(map[string]*roomPublisher),
+	}
+	if err := c.Create(r.Pipeline, nil); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// newEndpoint creates an endpoint whose candidates are sent for the given
+// publisher and subscriber.
+func (r *Room) newEndpoint(publisher, subscriber string, options WebRtcEndpointOptions) (*WebRtcEndpoint, *TrickleSession, error) {
+	var onDone func(string) error
+	if r.options.OnIceGatheringDone != nil {
+		onDone = func(string) error {
+			return r.options.OnIceGatheringDone(publisher, subscriber)
+		}
+	}
+	return newTricklePeer(r.Pipeline, publisher, options,
+		func(_ string, candidate IceCandidate) error {
+			return r.options.OnIceCandidate(publisher, subscriber, candidate)
+		}, onDone)
+}
+
+// Publish negotiates the endpoint receiving the media of participant id
+// from its offer, and gives the answer to sendAnswer.
+func (r *Room) Publish(id, offer string, sendAnswer func(answer string) error) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	switch {
+	case r.released:
+		return ErrRoomReleased
+	case r.publishers[id] != nil:
+		return ErrPeerExists
+	case r.options.MaxPublishers > 0 && len(r.publishers) >= r.options.MaxPublishers:
+		return ErrRoomFull
+	}
+
+	endpoint, trickle, err := r.newEndpoint(id, "", WebRtcEndpointOptions{Recvonly: true})
+	if err != nil {
+		return err
+	}
+	if err := trickle.AcceptOffer(offer, sendAnswer); err != nil {
+		trickle.Close()
+		endpoint.Release()
+		return err
+	}
+	r.publishers[id] = &roomPublisher{
+		endpoint:    endpoint,
+		trickle:     trickle,
+		subscribers: make(map[string]*subscription),
+	}
+	return nil
+}
+
+// Unpublish releases the endpoint of publisher id, and every subscription to
+// it.
+func (r *Room) Unpublish(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if _, ok := r.publishers[id]; !ok {
+		return fmt.Errorf("%w: %s", ErrPeerNotFound, id)
+	}
+	return r.unpublish(id)
+}
+
+// unpublish must be called with the lock held.
+func (r *Room) unpublish(id string) error {
+	p := r.publishers[id]
+	delete(r.publishers, id)
+
+	var ret error
+	for _, s := range p.subscribers {
+		if err := s.release(); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	p.trickle.Close()
+	if err := p.endpoint.Release(); err != nil && ret == nil {
+		ret = err
+	}
+	return ret
+}
+
+// Subscribe negotiates an endpoint sending the media of publisher to
+// subscriber from the offer of subscriber, and gives the answer to
+// sendAnswer. The lock is not held while KMS is called: the subscription is
+// registered before the offer is accepted, so its candidates are buffered
+// until then.
+func (r *Room) Subscribe(subscriber, publisher, offer string, sendAnswer func(answer string) error) error {
+	r.lock.Lock()
+	_, err := r.subscribable(subscriber, publisher)
+	r.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	endpoint, trickle, err := r.newEndpoint(publisher, subscriber, WebRtcEndpointOptions{Sendonly: true})
+	if err != nil {
+		return err
+	}
+	s := &subscription{endpoint: endpoint, trickle: trickle, muted: make(map[MediaType]bool)}
+
+	r.lock.Lock()
+	p, err := r.subscribable(subscriber, publisher)
+	if err != nil {
+		r.lock.Unlock()
+		s.release()
+		return err
+	}
+	p.subscribers[subscriber] = s
+	r.lock.Unlock()
+
+	err = p.endpoint.Connect(endpoint, "", "", "")
+	if err == nil {
+		err = trickle.AcceptOffer(offer, sendAnswer)
+	}
+	if err != nil {
+		// unless the publisher left and released it meanwhile
+		r.lock.Lock()
+		registered := p.subscribers[subscriber] == s
+		if registered {
+			delete(p.subscribers, subscriber)
+		}
+		r.lock.Unlock()
+		if registered {
+			s.release()
+		}
+		return err
+	}
+	return nil
+}
+
+// subscribable returns publisher if subscriber can subscribe to it. It must
+// be called with the lock held.
+func (r *Room) subscribable(subscriber, publisher string) (*roomPublisher, error) {
+	if r.released {
+		return nil, ErrRoomReleased
+	}
+	p, ok := r.publishers[publisher]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, publisher)
+	}
+	if _, ok := p.subscribers[subscriber]; ok {
+		return nil, ErrPeerExists
+	}
+	return p, nil
+}
+
+// Unsubscribe releases the subscription of subscriber to publisher.
+func (r *Room) Unsubscribe(subscriber, publisher string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s, err := r.subscription(subscriber, publisher)
+	if err != nil {
+		return err
+	}
+	delete(r.publishers[publisher].subscribers, subscriber)
+	return s.release()
+}
+
+// subscription must be called with the lock held.
+func (r *Room) subscription(subscriber, publisher string) (*subscription, error) {
+	p, ok := r.publishers[publisher]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPeerNotFound, publisher)
+	}
+	s, ok := p.subscribers[subscriber]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s is not subscribed to %s", ErrPeerNotFound, subscriber, publisher)
+	}
+	return s, nil
+}
+
+// SetMuted stops or resumes forwarding the media of type mediaType of
+// publisher to subscriber, by disconnecting or connecting that media only.
+func (r *Room) SetMuted(subscriber, publisher string, mediaType MediaType, muted bool) error {
+	if mediaType != MEDIATYPE_AUDIO && mediaType != MEDIATYPE_VIDEO {
+		return fmt.Errorf("kurento: cannot mute media type %q", mediaType)
+	}
+
+	r.lock.Lock()
+	s, err := r.subscription(subscriber, publisher)
+	var source *WebRtcEndpoint
+	if err == nil {
+		source = r.publishers[publisher].endpoint
+	}
+	r.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.muted[mediaType] == muted {
+		return nil
+	}
+	if muted {
+		err = source.Disconnect(s.endpoint, mediaType, "", "")
+	} else {
+		err = source.Connect(s.endpoint, mediaType, "", "")
+	}
+	if err != nil {
+		return err
+	}
+	s.muted[mediaType] = muted
+	return nil
+}
+
+// Muted returns the media types of publisher muted for subscriber.
+func (r *Room) Muted(subscriber, publisher string) ([]MediaType, error) {
+	r.lock.Lock()
+	s, err := r.subscription(subscriber, publisher)
+	r.lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	var ret []MediaType
+	for _, t := range []MediaType{MEDIATYPE_AUDIO, MEDIATYPE_VIDEO} {
+		if s.muted[t] {
+			ret = append(ret, t)
+		}
+	}
+	return ret, nil
+}
+
+// AddIceCandidate gives a candidate received from a participant to the
+// endpoint identified as in RoomOptions.OnIceCandidate.
+func (r *Room) AddIceCandidate(publisher, subscriber string, candidate IceCandidate) error {
+	r.lock.Lock()
+	var trickle *TrickleSession
+	if p, ok := r.publishers[publisher]; ok {
+		if subscriber == "" {
+			trickle = p.trickle
+		} else if s, ok := p.subscribers[subscriber]; ok {
+			trickle = s.trickle
+		}
+	}
+	r.lock.Unlock()
+
+	if trickle == nil {
+		return fmt.Errorf("%w: %s/%s", ErrPeerNotFound, publisher, subscriber)
+	}
+	return trickle.AddRemoteCandidate(candidate)
+}
+
+// Leave unpublishes participant id if it publishes, and releases its
+// subscriptions.
+func (r *Room) Leave(id string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var ret error
+	for _, p := range r.publishers {
+		if s, ok := p.subscribers[id]; ok {
+			delete(p.subscribers, id)
+			if err := s.release(); err != nil && ret == nil {
+				ret = err
+			}
+		}
+	}
+	if _, ok := r.publishers[id]; ok {
+		if err := r.unpublish(id); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
+
+// Publishers returns the IDs of the publishers, sorted.
+func (r *Room) Publishers() []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := make([]string, 0, len(r.publishers))
+	for id := range r.publishers {
+		ret = append(ret, id)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Subscriptions returns the publishers subscriber is subscribed to, sorted.
+func (r *Room) Subscriptions(subscriber string) []string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var ret []string
+	for id, p := range r.publishers {
+		if _, ok := p.subscribers[subscriber]; ok {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// FanOut returns the number of subscribers of publisher, i.e. the number of
+// endpoints its media is forwarded to.
+func (r *Room) FanOut(publisher string) int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if p, ok := r.publishers[publisher]; ok {
+		return len(p.subscribers)
+	}
+	return 0
+}
+
+// FanOuts returns the FanOut of every publisher.
+func (r *Room) FanOuts() map[string]int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	ret := make(map[string]int, len(r.publishers))
+	for id, p := range r.publishers {
+		ret[id] = len(p.subscribers)
+	}
+	return ret
+}
+
+// Release releases the pipeline, and every endpoint with it.
+func (r *Room) Release() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.released {
+		return nil
+	}
+	r.released = true
+	for _, p := range r.publishers {
+		p.trickle.Close()
+		for _, s := range p.subscribers {
+			s.trickle.Close()
+		}
+	}
+	r.publishers = nil
+	return r.Pipeline.Release()
+}
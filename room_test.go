@@ -0,0 +1,174 @@
+package kurento_test
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// roomEndpoint expects the creation and negotiation of an endpoint of the
+// room "room". It stops at the request named fail, which fails.
+func (s *script) roomEndpoint(id string, options map[string]interface{}, source, fail string) {
+	options["mediaPipeline"] = "room"
+	s.call("create", map[string]interface{}{"type": "WebRtcEndpoint", "constructorParams": options}, id)
+	s.subscribe(id, "IceCandidateFound", id+"/found")
+	s.subscribe(id, "IceGatheringDone", id+"/done")
+	if source != "" {
+		if fail == "connect" {
+			s.fail("invoke", map[string]interface{}{"object": source, "operation": "connect"}, 40101, "failed")
+			return
+		}
+		s.call("invoke", map[string]interface{}{
+			"object":          source,
+			"operation":       "connect",
+			"operationParams": map[string]interface{}{"sink": id},
+		}, nil)
+	}
+	s.invoke(id, "processOffer", "answer "+id)
+	s.invoke(id, "gatherCandidates", true)
+}
+
+// releaseRoomEndpoint expects the release of an endpoint of the room.
+func (s *script) releaseRoomEndpoint(id string) {
+	s.unsubscribe(id)
+	s.unsubscribe(id)
+	s.release(id)
+}
+
+func TestRoom(t *testing.T) {
+	s := &script{}
+	s.create("MediaPipeline", "room")
+	s.roomEndpoint("room/alice", map[string]interface{}{"recvonly": true}, "", "")
+	s.roomEndpoint("room/bob-alice", map[string]interface{}{"sendonly": true}, "room/alice", "")
+	s.event("room/bob-alice", "IceCandidateFound", map[string]interface{}{
+		"candidate": map[string]interface{}{"candidate": "local", "sdpMid": "0", "sdpMLineIndex": 0},
+	})
+	// a subscription which cannot be connected
+	s.roomEndpoint("room/carol-alice", map[string]interface{}{"sendonly": true}, "room/alice", "connect")
+	s.releaseRoomEndpoint("room/carol-alice")
+	s.call("invoke", map[string]interface{}{
+		"object":          "room/alice",
+		"operation":       "disconnect",
+		"operationParams": map[string]interface{}{"sink": "room/bob-alice", "mediaType": "VIDEO"},
+	}, nil)
+	s.call("invoke", map[string]interface{}{
+		"object":          "room/bob-alice",
+		"operation":       "addIceCandidate",
+		"operationParams": map[string]interface{}{"candidate": map[string]interface{}{"candidate": "remote"}},
+	}, nil)
+	s.releaseRoomEndpoint("room/bob-alice")
+	s.releaseRoomEndpoint("room/alice")
+	s.release("room")
+	conn, srv := s.serve(t)
+
+	if _, err := kurento.NewRoom(conn, kurento.RoomOptions{}); err == nil {
+		t.Fatal("room without OnIceCandidate")
+	}
+	var lock sync.Mutex
+	var candidates []string
+	found := make(chan bool, 1)
+	room, err := kurento.NewRoom(conn, kurento.RoomOptions{
+		MaxPublishers: 1,
+		OnIceCandidate: func(publisher, subscriber string, c kurento.IceCandidate) error {
+			lock.Lock()
+			defer lock.Unlock()
+			candidates = append(candidates, fmt.Sprintf("%s>%s %s", publisher, subscriber, c.Candidate))
+			found <- true
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// errKMS stands for any error of KMS
+	errKMS := errors.New("KMS error")
+	answers := make(map[string]string)
+	answer := func(id string) func(string) error {
+		return func(sdp string) error {
+			answers[id] = sdp
+			return nil
+		}
+	}
+	steps := []struct {
+		name   string
+		action func() error
+		want   error
+	}{
+		{"publish", func() error { return room.Publish("alice", "offer", answer("alice")) }, nil},
+		{"publish again", func() error { return room.Publish("alice", "offer", answer("alice")) }, kurento.ErrPeerExists},
+		{"room full", func() error { return room.Publish("bob", "offer", answer("bob")) }, kurento.ErrRoomFull},
+		{"subscribe", func() error {
+			// the subscription is registered before the offer is accepted
+			return room.Subscribe("bob", "alice", "offer", func(sdp string) error {
+				if subs := room.Subscriptions("bob"); len(subs) != 1 {
+					t.Errorf("subscriptions when answering: %v", subs)
+				}
+				return answer("bob")(sdp)
+			})
+		}, nil},
+		{"subscribe again", func() error { return room.Subscribe("bob", "alice", "offer", answer("bob")) }, kurento.ErrPeerExists},
+		{"no publisher", func() error { return room.Subscribe("bob", "dave", "offer", answer("bob")) }, kurento.ErrPeerNotFound},
+		{"connect fails", func() error { return room.Subscribe("carol", "alice", "offer", answer("carol")) }, errKMS},
+		{"mute", func() error { return room.SetMuted("bob", "alice", kurento.MEDIATYPE_VIDEO, true) }, nil},
+		// already muted
+		{"mute again", func() error { return room.SetMuted("bob", "alice", kurento.MEDIATYPE_VIDEO, true) }, nil},
+		{"not subscribed", func() error { return room.SetMuted("carol", "alice", kurento.MEDIATYPE_AUDIO, true) }, kurento.ErrPeerNotFound},
+		{"candidate", func() error {
+			return room.AddIceCandidate("alice", "bob", kurento.IceCandidate{Candidate: "remote"})
+		}, nil},
+		{"unknown endpoint", func() error {
+			return room.AddIceCandidate("alice", "carol", kurento.IceCandidate{Candidate: "remote"})
+		}, kurento.ErrPeerNotFound},
+	}
+	for _, step := range steps {
+		err := step.action()
+		if step.want == errKMS {
+			if err == nil {
+				t.Fatalf("%s: no error", step.name)
+			}
+			continue
+		}
+		if !errors.Is(err, step.want) {
+			t.Fatalf("%s: err = %v, want %v", step.name, err, step.want)
+		}
+	}
+	wait(t, found)
+
+	if muted, err := room.Muted("bob", "alice"); err != nil || fmt.Sprint(muted) != "[VIDEO]" {
+		t.Errorf("Muted() = %v, %v", muted, err)
+	}
+	if got := fmt.Sprint(room.Publishers(), room.Subscriptions("bob"), room.Subscriptions("carol"), room.FanOuts()); got != "[alice] [alice] [] map[alice:1]" {
+		t.Errorf("room = %s", got)
+	}
+	if answers["alice"] != "answer room/alice" || answers["bob"] != "answer room/bob-alice" {
+		t.Errorf("answers = %v", answers)
+	}
+	if err := room.SetMuted("bob", "alice", kurento.MEDIATYPE_DATA, true); err == nil {
+		t.Error("data muted")
+	}
+
+	// the subscriptions to alice are released with it
+	if err := room.Leave("alice"); err != nil {
+		t.Fatal(err)
+	}
+	if room.FanOut("alice") != 0 || len(room.Publishers()) != 0 || len(room.Subscriptions("bob")) != 0 {
+		t.Errorf("publishers %v left", room.Publishers())
+	}
+	if err := room.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := room.Publish("alice", "offer", answer("alice")); !errors.Is(err, kurento.ErrRoomReleased) {
+		t.Errorf("Publish() after Release = %v", err)
+	}
+	done(t, srv)
+
+	lock.Lock()
+	defer lock.Unlock()
+	if fmt.Sprint(candidates) != "[alice>bob local]" {
+		t.Errorf("candidates = %q", candidates)
+	}
+}
@@ -0,0 +1,245 @@
+package sdp
+
+import (
+	"strconv"
+	"strings"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// Codec is an RTP payload format of a media section, built from its
+// "a=rtpmap" and "a=fmtp" lines.
+type Codec struct {
+	PayloadType int
+	Name        string
+	ClockRate   int
+	Channels    int
+	Fmtp        string
+}
+
+// Codecs returns the codecs of the section, in order of preference.
+func (m *Media) Codecs() []Codec {
+	rtpmap := make(map[string]string)
+	for _, v := range m.Attributes("rtpmap") {
+		pt, desc, _ := strings.Cut(v, " ")
+		rtpmap[pt] = desc
+	}
+	fmtp := make(map[string]string)
+	for _, v := range m.Attributes("fmtp") {
+		pt, params, _ := strings.Cut(v, " ")
+		fmtp[pt] = params
+	}
+
+	var ret []Codec
+	for _, f := range m.Formats {
+		pt, err := strconv.Atoi(f)
+		if err != nil {
+			continue
+		}
+		c := Codec{PayloadType: pt, Fmtp: fmtp[f]}
+		parts := strings.Split(rtpmap[f], "/")
+		c.Name = parts[0]
+		if len(parts) > 1 {
+			c.ClockRate, _ = strconv.Atoi(parts[1])
+		}
+		if len(parts) > 2 {
+			c.Channels, _ = strconv.Atoi(parts[2])
+		}
+		ret = append(ret, c)
+	}
+	return ret
+}
+
+// RestrictCodecs keeps only the codecs of the section whose name is in names
+// (case insensitive), in their original order. Retransmission and FEC
+// payloads ("rtx", "red", "ulpfec"...) are kept only if they refer to a kept
+// codec through their "apt" parameter.
+func (m *Media) RestrictCodecs(names ...string) {
+	allowed := make(map[string]bool)
+	for _, n := range names {
+		allowed[strings.ToLower(n)] = true
+	}
+
+	codecs := m.Codecs()
+	kept := make(map[string]bool)
+	for _, c := range codecs {
+		if allowed[strings.ToLower(c.Name)] {
+			kept[strconv.Itoa(c.PayloadType)] = true
+		}
+	}
+	for _, c := range codecs {
+		if apt := fmtpParam(c.Fmtp, "apt"); apt != "" && kept[apt] {
+			kept[strconv.Itoa(c.PayloadType)] = true
+		}
+	}
+
+	formats := m.Formats[:0]
+	for _, f := range m.Formats {
+		if kept[f] {
+			formats = append(formats, f)
+		}
+	}
+	m.Formats = formats
+
+	keep := func(value string) bool {
+		pt, _, _ := strings.Cut(value, " ")
+		return kept[pt]
+	}
+	for _, key := range []string{"rtpmap", "fmtp", "rtcp-fb"} {
+		m.RemoveAttributes(key, keep)
+	}
+}
+
+func fmtpParam(fmtp, name string) string {
+	for _, p := range strings.Split(fmtp, ";") {
+		k, v, _ := strings.Cut(strings.TrimSpace(p), "=")
+		if k == name {
+			return v
+		}
+	}
+	return ""
+}
+
+// sdpMediaType is the "m=" media of a Kurento media type.
+func sdpMediaType(t kurento.MediaType) string {
+	if t == kurento.MEDIATYPE_DATA {
+		return "application"
+	}
+	return strings.ToLower(string(t))
+}
+
+// MediaOfType returns the media sections of the given type.
+func (d *SessionDescription) MediaOfType(t kurento.MediaType) []*Media {
+	var ret []*Media
+	for _, m := range d.Media {
+		if m.Type == sdpMediaType(t) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+// RestrictVideoCodecs keeps only the given codecs in the video sections.
+func (d *SessionDescription) RestrictVideoCodecs(codecs ...kurento.VideoCodec) {
+	var names []string
+	for _, c := range codecs {
+		names = append(names, string(c))
+	}
+	for _, m := range d.MediaOfType(kurento.MEDIATYPE_VIDEO) {
+		m.RestrictCodecs(names...)
+	}
+}
+
+// RestrictAudioCodecs keeps only the given codecs in the audio sections.
+func (d *SessionDescription) RestrictAudioCodecs(codecs ...kurento.AudioCodec) {
+	var names []string
+	for _, c := range codecs {
+		names = append(names, string(c))
+	}
+	for _, m := range d.MediaOfType(kurento.MEDIATYPE_AUDIO) {
+		m.RestrictCodecs(names...)
+	}
+}
+
+// SetBandwidth sets the "b=AS" line, in kbps, of every section of type t.
+func (d *SessionDescription) SetBandwidth(t kurento.MediaType, kbps int) {
+	for _, m := range d.MediaOfType(t) {
+		m.SetBandwidth("AS", kbps)
+	}
+}
+
+// RemoveMedia removes the sections of type t, and their identifiers from the
+// BUNDLE group. Use it on offers; answers must keep every section of the
+// offer, see DisableMedia.
+func (d *SessionDescription) RemoveMedia(t kurento.MediaType) {
+	removed := make(map[string]bool)
+	media := d.Media[:0]
+	for _, m := range d.Media {
+		if m.Type == sdpMediaType(t) {
+			if mid := m.Mid(); mid != "" {
+				removed[mid] = true
+			}
+			continue
+		}
+		media = append(media, m)
+	}
+	d.Media = media
+
+	for i, l := range d.Lines {
+		if l.Type != 'a' || !strings.HasPrefix(l.Value, "group:") {
+			continue
+		}
+		fields := strings.Fields(l.Value)
+		kept := fields[:1]
+		for _, mid := range fields[1:] {
+			if !removed[mid] {
+				kept = append(kept, mid)
+			}
+		}
+		d.Lines[i].Value = strings.Join(kept, " ")
+	}
+}
+
+// DisableMedia rejects the sections of type t by setting their port to zero
+// and marking them inactive, keeping the number of sections unchanged.
+func (d *SessionDescription) DisableMedia(t kurento.MediaType) {
+	for _, m := range d.MediaOfType(t) {
+		m.Port = 0
+		m.SetDirection("inactive")
+	}
+}
+
+// VideoCodec returns the preferred codec of the first active video section,
+// which is the negotiated codec in an answer. ok is false if there is no
+// such section or its codec is not a known VideoCodec.
+func (d *SessionDescription) VideoCodec() (codec kurento.VideoCodec, ok bool) {
+	name, ok := d.firstCodec(kurento.MEDIATYPE_VIDEO)
+	for _, c := range []kurento.VideoCodec{kurento.VIDEOCODEC_VP8, kurento.VIDEOCODEC_H264, kurento.VIDEOCODEC_RAW} {
+		if ok && strings.EqualFold(name, string(c)) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// AudioCodec returns the preferred codec of the first active audio section,
+// which is the negotiated codec in an answer. ok is false if there is no
+// such section or its codec is not a known AudioCodec.
+func (d *SessionDescription) AudioCodec() (codec kurento.AudioCodec, ok bool) {
+	name, ok := d.firstCodec(kurento.MEDIATYPE_AUDIO)
+	for _, c := range []kurento.AudioCodec{kurento.AUDIOCODEC_OPUS, kurento.AUDIOCODEC_PCMU, kurento.AUDIOCODEC_RAW} {
+		if ok && strings.EqualFold(name, string(c)) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+func (d *SessionDescription) firstCodec(t kurento.MediaType) (string, bool) {
+	for _, m := range d.MediaOfType(t) {
+		if m.Port == 0 || m.Direction() == "inactive" {
+			continue
+		}
+		if codecs := m.Codecs(); len(codecs) > 0 {
+			return codecs[0].Name, true
+		}
+	}
+	return "", false
+}
+
+// Transform returns a kurento.SdpTransform which parses the description,
+// applies fns in order and serializes the result.
+func Transform(fns ...func(*SessionDescription) error) kurento.SdpTransform {
+	return func(sdp string) (string, error) {
+		d, err := Parse(sdp)
+		if err != nil {
+			return "", err
+		}
+		for _, fn := range fns {
+			if err := fn(d); err != nil {
+				return "", err
+			}
+		}
+		return d.String(), nil
+	}
+}
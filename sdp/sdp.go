@@ -0,0 +1,267 @@
+// Package sdp parses, edits and serializes the session descriptions
+// exchanged with SdpEndpoint.ProcessOffer, ProcessAnswer and GenerateOffer.
+//
+// The model is lossless: lines that are not interpreted are kept in order, so
+// a description that is parsed then serialized is unchanged, except for line
+// endings which are always CRLF.
+package sdp
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Line is a "<type>=<value>" line of a description.
+type Line struct {
+	Type  byte
+	Value string
+}
+
+func (l Line) String() string {
+	return string(l.Type) + "=" + l.Value
+}
+
+// Media is a media section, starting with its "m=" line.
+type Media struct {
+	// Type is the media type of the section: audio, video, application...
+	Type     string
+	Port     int
+	NumPorts int
+	Proto    string
+	// Formats are the formats of the "m=" line, payload types for RTP.
+	Formats []string
+
+	// Lines are the lines following the "m=" line, in order.
+	Lines []Line
+}
+
+// SessionDescription is a parsed SDP.
+type SessionDescription struct {
+	// Lines are the session level lines, in order.
+	Lines []Line
+	Media []*Media
+}
+
+// Parse reads a session description.
+func Parse(sdp string) (*SessionDescription, error) {
+	d := &SessionDescription{}
+	var current *Media
+
+	for n, raw := range strings.Split(sdp, "\n") {
+		raw = strings.TrimRight(raw, "\r")
+		if raw == "" {
+			continue
+		}
+		if len(raw) < 2 || raw[1] != '=' {
+			return nil, fmt.Errorf("sdp: line %d: invalid line %q", n+1, raw)
+		}
+		line := Line{Type: raw[0], Value: raw[2:]}
+
+		if line.Type == 'm' {
+			m, err := parseMediaLine(line.Value)
+			if err != nil {
+				return nil, fmt.Errorf("sdp: line %d: %w", n+1, err)
+			}
+			d.Media = append(d.Media, m)
+			current = m
+			continue
+		}
+
+		if current != nil {
+			current.Lines = append(current.Lines, line)
+		} else {
+			d.Lines = append(d.Lines, line)
+		}
+	}
+
+	if len(d.Lines) == 0 || d.Lines[0].Type != 'v' {
+		return nil, errors.New("sdp: missing version line")
+	}
+	return d, nil
+}
+
+func parseMediaLine(value string) (*Media, error) {
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid media line %q", value)
+	}
+	m := &Media{
+		Type:    fields[0],
+		Proto:   fields[2],
+		Formats: fields[3:],
+	}
+	port := fields[1]
+	if i := strings.Index(port, "/"); i >= 0 {
+		n, err := strconv.Atoi(port[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", port)
+		}
+		m.NumPorts = n
+		port = port[:i]
+	}
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", port)
+	}
+	m.Port = p
+	return m, nil
+}
+
+// String serializes the description.
+func (d *SessionDescription) String() string {
+	var b strings.Builder
+	for _, l := range d.Lines {
+		b.WriteString(l.String())
+		b.WriteString("\r\n")
+	}
+	for _, m := range d.Media {
+		b.WriteString(m.String())
+	}
+	return b.String()
+}
+
+// String serializes the media section, "m=" line included.
+func (m *Media) String() string {
+	var b strings.Builder
+	port := strconv.Itoa(m.Port)
+	if m.NumPorts > 0 {
+		port += "/" + strconv.Itoa(m.NumPorts)
+	}
+	fields := append([]string{m.Type, port, m.Proto}, m.Formats...)
+	b.WriteString("m=" + strings.Join(fields, " ") + "\r\n")
+	for _, l := range m.Lines {
+		b.WriteString(l.String())
+		b.WriteString("\r\n")
+	}
+	return b.String()
+}
+
+// Attribute returns the value of the first "a=<key>" or "a=<key>:<value>"
+// line of the section. ok is false if there is no such attribute.
+func (m *Media) Attribute(key string) (value string, ok bool) {
+	values := m.Attributes(key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Attributes returns the values of every "a=<key>" line of the section.
+func (m *Media) Attributes(key string) []string {
+	return attributes(m.Lines, key)
+}
+
+// AddAttribute appends an "a=<key>:<value>" line, or "a=<key>" if value is
+// empty.
+func (m *Media) AddAttribute(key, value string) {
+	m.Lines = append(m.Lines, attributeLine(key, value))
+}
+
+// RemoveAttributes removes every "a=<key>" line for which keep returns
+// false. A nil keep removes all of them.
+func (m *Media) RemoveAttributes(key string, keep func(value string) bool) {
+	m.Lines = removeAttributes(m.Lines, key, keep)
+}
+
+// Attribute returns the value of the first session level "a=<key>" line.
+func (d *SessionDescription) Attribute(key string) (value string, ok bool) {
+	values := attributes(d.Lines, key)
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// Mid returns the media identifier of the section, or "" if it has none.
+func (m *Media) Mid() string {
+	mid, _ := m.Attribute("mid")
+	return mid
+}
+
+// Direction returns the direction attribute of the section: sendrecv,
+// sendonly, recvonly or inactive. A section without direction is sendrecv.
+func (m *Media) Direction() string {
+	for _, dir := range []string{"sendrecv", "sendonly", "recvonly", "inactive"} {
+		if _, ok := m.Attribute(dir); ok {
+			return dir
+		}
+	}
+	return "sendrecv"
+}
+
+// SetDirection replaces the direction attribute of the section.
+func (m *Media) SetDirection(direction string) {
+	for _, dir := range []string{"sendrecv", "sendonly", "recvonly", "inactive"} {
+		m.RemoveAttributes(dir, nil)
+	}
+	m.AddAttribute(direction, "")
+}
+
+// Bandwidth returns the value of the "b=<modifier>:" line of the section.
+func (m *Media) Bandwidth(modifier string) (int, bool) {
+	for _, l := range m.Lines {
+		if l.Type == 'b' && strings.HasPrefix(l.Value, modifier+":") {
+			v, err := strconv.Atoi(strings.TrimPrefix(l.Value, modifier+":"))
+			return v, err == nil
+		}
+	}
+	return 0, false
+}
+
+// SetBandwidth replaces the "b=<modifier>:<value>" line of the section, e.g.
+// SetBandwidth("AS", 500) for 500 kbps. The line is placed after the "i=" and
+// "c=" lines, as required by RFC 4566.
+func (m *Media) SetBandwidth(modifier string, value int) {
+	line := Line{Type: 'b', Value: modifier + ":" + strconv.Itoa(value)}
+
+	lines := make([]Line, 0, len(m.Lines)+1)
+	pos := 0
+	for _, l := range m.Lines {
+		if l.Type == 'b' && strings.HasPrefix(l.Value, modifier+":") {
+			continue
+		}
+		lines = append(lines, l)
+		if l.Type == 'i' || l.Type == 'c' {
+			pos = len(lines)
+		}
+	}
+	lines = append(lines[:pos], append([]Line{line}, lines[pos:]...)...)
+	m.Lines = lines
+}
+
+func attributes(lines []Line, key string) []string {
+	var ret []string
+	for _, l := range lines {
+		if l.Type != 'a' {
+			continue
+		}
+		k, v, _ := strings.Cut(l.Value, ":")
+		if k == key {
+			ret = append(ret, v)
+		}
+	}
+	return ret
+}
+
+func attributeLine(key, value string) Line {
+	if value == "" {
+		return Line{Type: 'a', Value: key}
+	}
+	return Line{Type: 'a', Value: key + ":" + value}
+}
+
+func removeAttributes(lines []Line, key string, keep func(string) bool) []Line {
+	ret := lines[:0]
+	for _, l := range lines {
+		if l.Type == 'a' {
+			k, v, _ := strings.Cut(l.Value, ":")
+			if k == key && (keep == nil || !keep(v)) {
+				continue
+			}
+		}
+		ret = append(ret, l)
+	}
+	return ret
+}
@@ -0,0 +1,260 @@
+package sdp
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// offer is a browser offer with audio, video and data, with LF line endings.
+const offer = `v=0
+o=- 4611731400430051336 2 IN IP4 127.0.0.1
+s=-
+t=0 0
+a=group:BUNDLE 0 1 2
+m=audio 9 UDP/TLS/RTP/SAVPF 111 0
+c=IN IP4 0.0.0.0
+a=mid:0
+a=sendrecv
+a=rtpmap:111 opus/48000/2
+a=fmtp:111 minptime=10;useinbandfec=1
+a=rtpmap:0 PCMU/8000
+m=video 9 UDP/TLS/RTP/SAVPF 96 97 102 103
+c=IN IP4 0.0.0.0
+a=mid:1
+a=sendrecv
+a=rtpmap:96 VP8/90000
+a=rtcp-fb:96 nack
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+a=rtpmap:102 H264/90000
+a=fmtp:102 profile-level-id=42e01f
+a=rtpmap:103 rtx/90000
+a=fmtp:103 apt=102
+m=application 9 UDP/DTLS/SCTP webrtc-datachannel
+c=IN IP4 0.0.0.0
+a=mid:2
+a=sctp-port:5000
+`
+
+func crlf(s string) string {
+	return strings.ReplaceAll(s, "\n", "\r\n")
+}
+
+// edit returns offer with the lines of old replaced by those of new.
+func edit(old, new string) string {
+	if !strings.Contains(offer, old) {
+		panic("not in the offer: " + old)
+	}
+	return crlf(strings.Replace(offer, old, new, 1))
+}
+
+func TestRoundTrip(t *testing.T) {
+	d, err := Parse(offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := d.String(); got != crlf(offer) {
+		t.Errorf("String() =\n%s\nwant\n%s", got, crlf(offer))
+	}
+	if len(d.Media) != 3 || d.Media[1].Type != "video" || d.Media[1].Port != 9 || d.Media[2].Proto != "UDP/DTLS/SCTP" {
+		t.Errorf("media = %+v", d.Media)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		sdp  string
+	}{
+		{"empty", ""},
+		{"no version", "s=-\r\n"},
+		{"invalid line", "v=0\r\nnot a line\r\n"},
+		{"short media line", "v=0\r\nm=audio 9\r\n"},
+		{"invalid port", "v=0\r\nm=audio x RTP/AVP 0\r\n"},
+		{"invalid number of ports", "v=0\r\nm=audio 9/x RTP/AVP 0\r\n"},
+	}
+	for _, tt := range tests {
+		if _, err := Parse(tt.sdp); err == nil {
+			t.Errorf("%s: parsed", tt.name)
+		}
+	}
+}
+
+func TestTransforms(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(*SessionDescription) error
+		want string
+	}{
+		{
+			name: "restrict video codecs",
+			fn: func(d *SessionDescription) error {
+				d.RestrictVideoCodecs(kurento.VIDEOCODEC_H264)
+				return nil
+			},
+			want: edit(`m=video 9 UDP/TLS/RTP/SAVPF 96 97 102 103
+c=IN IP4 0.0.0.0
+a=mid:1
+a=sendrecv
+a=rtpmap:96 VP8/90000
+a=rtcp-fb:96 nack
+a=rtpmap:97 rtx/90000
+a=fmtp:97 apt=96
+a=rtpmap:102`, `m=video 9 UDP/TLS/RTP/SAVPF 102 103
+c=IN IP4 0.0.0.0
+a=mid:1
+a=sendrecv
+a=rtpmap:102`),
+		},
+		{
+			name: "restrict audio codecs",
+			fn: func(d *SessionDescription) error {
+				d.RestrictAudioCodecs(kurento.AUDIOCODEC_PCMU)
+				return nil
+			},
+			want: edit(`m=audio 9 UDP/TLS/RTP/SAVPF 111 0
+c=IN IP4 0.0.0.0
+a=mid:0
+a=sendrecv
+a=rtpmap:111 opus/48000/2
+a=fmtp:111 minptime=10;useinbandfec=1
+`, `m=audio 9 UDP/TLS/RTP/SAVPF 0
+c=IN IP4 0.0.0.0
+a=mid:0
+a=sendrecv
+`),
+		},
+		{
+			name: "bandwidth",
+			fn: func(d *SessionDescription) error {
+				d.SetBandwidth(kurento.MEDIATYPE_VIDEO, 500)
+				d.SetBandwidth(kurento.MEDIATYPE_VIDEO, 300)
+				return nil
+			},
+			want: edit("a=mid:1\n", "b=AS:300\na=mid:1\n"),
+		},
+		{
+			name: "remove media",
+			fn: func(d *SessionDescription) error {
+				d.RemoveMedia(kurento.MEDIATYPE_AUDIO)
+				return nil
+			},
+			want: edit(`a=group:BUNDLE 0 1 2
+m=audio 9 UDP/TLS/RTP/SAVPF 111 0
+c=IN IP4 0.0.0.0
+a=mid:0
+a=sendrecv
+a=rtpmap:111 opus/48000/2
+a=fmtp:111 minptime=10;useinbandfec=1
+a=rtpmap:0 PCMU/8000
+`, "a=group:BUNDLE 1 2\n"),
+		},
+		{
+			name: "disable media",
+			fn: func(d *SessionDescription) error {
+				d.DisableMedia(kurento.MEDIATYPE_DATA)
+				return nil
+			},
+			want: edit(`m=application 9 UDP/DTLS/SCTP webrtc-datachannel
+c=IN IP4 0.0.0.0
+a=mid:2
+a=sctp-port:5000
+`, `m=application 0 UDP/DTLS/SCTP webrtc-datachannel
+c=IN IP4 0.0.0.0
+a=mid:2
+a=sctp-port:5000
+a=inactive
+`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Transform(tt.fn)(offer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformErrors(t *testing.T) {
+	if _, err := Transform()("s=-"); err == nil {
+		t.Error("invalid description transformed")
+	}
+	failed := errors.New("failed")
+	calls := 0
+	_, err := Transform(
+		func(*SessionDescription) error { calls++; return failed },
+		func(*SessionDescription) error { calls++; return nil },
+	)(offer)
+	if !errors.Is(err, failed) || calls != 1 {
+		t.Errorf("err = %v after %d calls", err, calls)
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	d, err := Parse(offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	codecs := d.Media[0].Codecs()
+	want := []Codec{
+		{PayloadType: 111, Name: "opus", ClockRate: 48000, Channels: 2, Fmtp: "minptime=10;useinbandfec=1"},
+		{PayloadType: 0, Name: "PCMU", ClockRate: 8000},
+	}
+	if len(codecs) != len(want) || codecs[0] != want[0] || codecs[1] != want[1] {
+		t.Errorf("Codecs() = %+v, want %+v", codecs, want)
+	}
+
+	tests := []struct {
+		name  string
+		edit  func(*SessionDescription)
+		video kurento.VideoCodec
+		audio kurento.AudioCodec
+	}{
+		{"offer", func(*SessionDescription) {}, kurento.VIDEOCODEC_VP8, kurento.AUDIOCODEC_OPUS},
+		{"restricted", func(d *SessionDescription) {
+			d.RestrictVideoCodecs(kurento.VIDEOCODEC_H264)
+			d.RestrictAudioCodecs(kurento.AUDIOCODEC_PCMU)
+		}, kurento.VIDEOCODEC_H264, kurento.AUDIOCODEC_PCMU},
+		{"disabled", func(d *SessionDescription) {
+			d.DisableMedia(kurento.MEDIATYPE_VIDEO)
+			d.RemoveMedia(kurento.MEDIATYPE_AUDIO)
+		}, "", ""},
+	}
+	for _, tt := range tests {
+		d, err := Parse(offer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tt.edit(d)
+		if video, _ := d.VideoCodec(); video != tt.video {
+			t.Errorf("%s: VideoCodec() = %q, want %q", tt.name, video, tt.video)
+		}
+		if audio, _ := d.AudioCodec(); audio != tt.audio {
+			t.Errorf("%s: AudioCodec() = %q, want %q", tt.name, audio, tt.audio)
+		}
+	}
+}
+
+func TestDirection(t *testing.T) {
+	d, err := Parse(offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := d.Media[2]
+	if dir := m.Direction(); dir != "sendrecv" {
+		t.Errorf("default direction = %s", dir)
+	}
+	m.SetDirection("recvonly")
+	m.SetDirection("sendonly")
+	if dir := m.Direction(); dir != "sendonly" || len(m.Attributes("recvonly")) != 0 {
+		t.Errorf("direction = %s, lines %v", dir, m.Lines)
+	}
+}
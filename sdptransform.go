@@ -0,0 +1,48 @@
+package kurento
+
+// SdpTransform rewrites a session description, e.g. to restrict codecs or
+// add bandwidth lines. The sdp subpackage builds them with sdp.Transform.
+type SdpTransform func(sdp string) (string, error)
+
+func (t SdpTransform) apply(sdp string) (string, error) {
+	if t == nil {
+		return sdp, nil
+	}
+	return t(sdp)
+}
+
+// ProcessOfferWith is ProcessOffer with the offer rewritten by in before it
+// is sent to KMS, and the answer of KMS rewritten by out. A nil transform
+// leaves the description unchanged.
+func (elem *SdpEndpoint) ProcessOfferWith(offer string, in, out SdpTransform) (string, error) {
+	offer, err := in.apply(offer)
+	if err != nil {
+		return "", err
+	}
+	answer, err := elem.ProcessOffer(offer)
+	if err != nil {
+		return answer, err
+	}
+	return out.apply(answer)
+}
+
+// ProcessAnswerWith is ProcessAnswer with the answer rewritten by in before
+// it is sent to KMS.
+func (elem *SdpEndpoint) ProcessAnswerWith(answer string, in SdpTransform) (string, error) {
+	answer, err := in.apply(answer)
+	if err != nil {
+		return "", err
+	}
+	return elem.ProcessAnswer(answer)
+}
+
+// GenerateOfferWith is GenerateOffer with the offer of KMS rewritten by out
+// before it is returned. As with GenerateOffer, the media left false in
+// options are not offered.
+func (elem *SdpEndpoint) GenerateOfferWith(options OfferOptions, out SdpTransform) (string, error) {
+	offer, err := elem.GenerateOffer(options)
+	if err != nil {
+		return offer, err
+	}
+	return out.apply(offer)
+}
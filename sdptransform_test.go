@@ -0,0 +1,104 @@
+package kurento_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestSdpTransforms(t *testing.T) {
+	upper := kurento.SdpTransform(func(sdp string) (string, error) { return strings.ToUpper(sdp), nil })
+	failed := errors.New("failed")
+	fail := kurento.SdpTransform(func(string) (string, error) { return "", failed })
+
+	tests := []struct {
+		name    string
+		script  func(s *script)
+		call    func(elem *kurento.WebRtcEndpoint) (string, error)
+		want    string
+		wantErr error
+	}{
+		{
+			name: "process offer",
+			script: func(s *script) {
+				s.call("invoke", map[string]interface{}{
+					"operation":       "processOffer",
+					"operationParams": map[string]interface{}{"offer": "OFFER"},
+				}, "answer")
+			},
+			call: func(elem *kurento.WebRtcEndpoint) (string, error) {
+				return elem.ProcessOfferWith("offer", upper, upper)
+			},
+			want: "ANSWER",
+		},
+		{
+			name: "process offer without transforms",
+			script: func(s *script) {
+				s.call("invoke", map[string]interface{}{
+					"operation":       "processOffer",
+					"operationParams": map[string]interface{}{"offer": "offer"},
+				}, "answer")
+			},
+			call: func(elem *kurento.WebRtcEndpoint) (string, error) {
+				return elem.ProcessOfferWith("offer", nil, nil)
+			},
+			want: "answer",
+		},
+		{
+			name:   "offer transform failed",
+			script: func(s *script) {},
+			call: func(elem *kurento.WebRtcEndpoint) (string, error) {
+				return elem.ProcessOfferWith("offer", fail, upper)
+			},
+			wantErr: failed,
+		},
+		{
+			name: "process answer",
+			script: func(s *script) {
+				s.call("invoke", map[string]interface{}{
+					"operation":       "processAnswer",
+					"operationParams": map[string]interface{}{"answer": "ANSWER"},
+				}, "offer")
+			},
+			call: func(elem *kurento.WebRtcEndpoint) (string, error) {
+				return elem.ProcessAnswerWith("answer", upper)
+			},
+			want: "offer",
+		},
+		{
+			name: "generate offer",
+			script: func(s *script) {
+				s.call("invoke", map[string]interface{}{
+					"operation": "generateOffer",
+					"operationParams": map[string]interface{}{
+						"options": map[string]interface{}{"offerToReceiveAudio": true, "offerToReceiveVideo": false},
+					},
+				}, "offer")
+			},
+			call: func(elem *kurento.WebRtcEndpoint) (string, error) {
+				return elem.GenerateOfferWith(kurento.OfferOptions{OfferToReceiveAudio: true}, upper)
+			},
+			want: "OFFER",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &script{}
+			tt.script(s)
+			conn, srv := s.serve(t)
+			elem := &kurento.WebRtcEndpoint{}
+			kurento.HydrateMediaObject("pipe/webrtc", nil, conn, elem)
+
+			got, err := tt.call(elem)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			done(t, srv)
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package kurento
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Segment is a file written by a SegmentedRecorder.
+type Segment struct {
+	// Index of the segment, from 0.
+	Index int
+	Uri   string
+
+	// Start is when the recorder of the segment was started, End when it was
+	// stopped. Consecutive segments overlap by SegmentOptions.Overlap.
+	Start time.Time
+	End   time.Time
+}
+
+// SegmentOptions configure a SegmentedRecorder.
+type SegmentOptions struct {
+	// UriTemplate is a text/template giving the URI of each segment from a
+	// Segment with its Index and Start set, e.g.
+	// "file:///tmp/call-{{.Index}}-{{.Start.Unix}}.webm".
+	UriTemplate string
+
+	// Duration of a segment, before the next one starts.
+	Duration time.Duration
+
+	// Overlap is the time during which two consecutive segments are
+	// recorded, so no media is lost while the next recorder starts.
+	Overlap time.Duration
+
+	// MediaProfile and SourceMedia are as in RecordingOptions.
+	MediaProfile MediaProfileSpecType
+	SourceMedia  []MediaType
+
+	// OnSegmentComplete is called with every finalized segment. Optional.
+	OnSegmentComplete func(Segment)
+
+	// OnError is called when a segment cannot be started or finalized. The
+	// recorder keeps the current segment and tries again at the next
+	// rotation. Optional.
+	OnError func(error)
+}
+
+type segmentRecording struct {
+	segment Segment
+	session *RecordingSession
+}
+
+// SegmentedRecorder records an element into consecutive files of a fixed
+// duration. Each segment has its own RecorderEndpoint, as the URI of a
+// recorder cannot change: the next recorder is connected and started before
+// the previous one is stopped, so the source stays connected throughout.
+type SegmentedRecorder struct {
+	source   IMediaElement
+	options  SegmentOptions
+	template *template.Template
+
+	lock    sync.Mutex
+	current *segmentRecording
+	index   int
+	stop    chan struct{}
+	done    chan error
+}
+
+// NewSegmentedRecorder prepares the recording of source. Recording begins
+// with Start.
+func NewSegmentedRecorder(source IMediaElement, options SegmentOptions) (*SegmentedRecorder, error) {
+	if options.Duration <= 0 {
+		return nil, errors.New("kurento: segment duration must be positive")
+	}
+	if options.Overlap < 0 || options.Overlap >= options.Duration {
+		return nil, errors.New("kurento: segment overlap must be shorter than the duration")
+	}
+	tmpl, err := template.New("uri").Parse(options.UriTemplate)
+	if err != nil {
+		return nil, err
+	}
+	return &SegmentedRecorder{
+		source:   source,
+		options:  options,
+		template: tmpl,
+	}, nil
+}
+
+// newSegment creates and starts the recorder of the next segment.
+func (r *SegmentedRecorder) newSegment() (*segmentRecording, error) {
+	seg := Segment{Index: r.index, Start: time.Now()}
+	uri := &strings.Builder{}
+	if err := r.template.Execute(uri, seg); err != nil {
+		return nil, err
+	}
+	seg.Uri = uri.String()
+
+	session, err := NewRecordingSession(r.source, RecordingOptions{
+		Uri:          seg.Uri,
+		MediaProfile: r.options.MediaProfile,
+		SourceMedia:  r.options.SourceMedia,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := session.Start(); err != nil {
+		session.Close()
+		return nil, err
+	}
+	r.index++
+	return &segmentRecording{segment: seg, session: session}, nil
+}
+
+// finish finalizes a segment and reports it.
+func (r *SegmentedRecorder) finish(s *segmentRecording) error {
+	err := s.session.Close()
+	s.segment.End = time.Now()
+	if err != nil {
+		return err
+	}
+	if state, serr := s.session.State(); state == RecordingFailed {
+		return serr
+	}
+	if r.options.OnSegmentComplete != nil {
+		r.options.OnSegmentComplete(s.segment)
+	}
+	return nil
+}
+
+func (r *SegmentedRecorder) reportError(err error) {
+	if err != nil && r.options.OnError != nil {
+		r.options.OnError(err)
+	}
+}
+
+// Start records the first segment and starts the rotation.
+func (r *SegmentedRecorder) Start() error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.stop != nil {
+		return errors.New("kurento: segmented recorder already started")
+	}
+	first, err := r.newSegment()
+	if err != nil {
+		return err
+	}
+	r.current = first
+	r.stop = make(chan struct{})
+	r.done = make(chan error, 1)
+	go r.rotate(r.stop, r.done)
+	return nil
+}
+
+func (r *SegmentedRecorder) rotate(stop <-chan struct{}, done chan<- error) {
+	ticker := time.NewTicker(r.options.Duration)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-stop:
+			r.lock.Lock()
+			last := r.current
+			r.current = nil
+			r.lock.Unlock()
+			done <- r.finish(last)
+			return
+		}
+
+		r.lock.Lock()
+		next, err := r.newSegment()
+		r.lock.Unlock()
+		if err != nil {
+			r.reportError(err)
+			continue
+		}
+
+		select {
+		case <-time.After(r.options.Overlap):
+		case <-stop:
+		}
+		r.lock.Lock()
+		previous := r.current
+		r.current = next
+		r.lock.Unlock()
+		r.reportError(r.finish(previous))
+	}
+}
+
+// Current returns the segment being recorded, and false if stopped.
+func (r *SegmentedRecorder) Current() (Segment, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.current == nil {
+		return Segment{}, false
+	}
+	return r.current.segment, true
+}
+
+// Stop finalizes the current segment and stops the rotation. The error is
+// the one of the last segment, which is also reported through
+// OnSegmentComplete when successful.
+func (r *SegmentedRecorder) Stop() error {
+	r.lock.Lock()
+	stop, done := r.stop, r.done
+	r.stop = nil
+	r.lock.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	close(stop)
+	return <-done
+}
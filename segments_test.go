@@ -0,0 +1,87 @@
+package kurento_test
+
+import (
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestSegmentOptions(t *testing.T) {
+	tests := []struct {
+		name    string
+		options kurento.SegmentOptions
+		wantErr bool
+	}{
+		{"valid", kurento.SegmentOptions{UriTemplate: "file:///tmp/{{.Index}}.webm", Duration: time.Minute, Overlap: time.Second}, false},
+		{"no duration", kurento.SegmentOptions{UriTemplate: "file:///tmp/{{.Index}}.webm"}, true},
+		{"negative overlap", kurento.SegmentOptions{Duration: time.Minute, Overlap: -time.Second}, true},
+		{"overlap longer than a segment", kurento.SegmentOptions{Duration: time.Minute, Overlap: time.Minute}, true},
+		{"invalid template", kurento.SegmentOptions{UriTemplate: "file:///tmp/{{.Index", Duration: time.Minute}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := kurento.NewSegmentedRecorder(&kurento.WebRtcEndpoint{}, tt.options); (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSegmentedRecorderRotation(t *testing.T) {
+	s := &script{}
+	for i, id := range []string{"pipe/rec0", "pipe/rec1"} {
+		s.sourceMedia([]string{"AUDIO", "VIDEO"}, nil)
+		s.recorderOf(id, "AUDIO", "VIDEO")
+		s.call("invoke", map[string]interface{}{"object": id, "operation": "record"}, nil)
+		if i > 0 {
+			// the previous segment is finalized after the overlap
+			s.closeRecorderOf("pipe/rec0", "AUDIO", "VIDEO")
+		}
+	}
+	s.closeRecorderOf("pipe/rec1", "AUDIO", "VIDEO")
+	conn, srv := s.serve(t)
+
+	completed := make(chan kurento.Segment, 2)
+	r, err := kurento.NewSegmentedRecorder(newSource(conn), kurento.SegmentOptions{
+		UriTemplate:       "file:///tmp/seg-{{.Index}}.webm",
+		Duration:          200 * time.Millisecond,
+		Overlap:           10 * time.Millisecond,
+		OnSegmentComplete: func(seg kurento.Segment) { completed <- seg },
+		OnError:           func(err error) { t.Error(err) },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Start(); err == nil {
+		t.Error("started twice")
+	}
+
+	first := wait(t, completed)
+	current, ok := r.Current()
+	if !ok || current.Index != 1 || current.Uri != "file:///tmp/seg-1.webm" {
+		t.Errorf("current segment %+v", current)
+	}
+	if err := r.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	last := wait(t, completed)
+	done(t, srv)
+
+	if first.Index != 0 || first.Uri != "file:///tmp/seg-0.webm" || last.Index != 1 {
+		t.Errorf("segments %+v and %+v", first, last)
+	}
+	// consecutive segments overlap
+	if !last.Start.Before(first.End) || first.End.Before(first.Start) {
+		t.Errorf("segment 1 starts at %v, segment 0 ends at %v", last.Start, first.End)
+	}
+	if _, ok := r.Current(); ok {
+		t.Error("segment recorded after Stop")
+	}
+	if err := r.Stop(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,491 @@
+package kurento
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// SnapshotObject is an object created through a connection.
+type SnapshotObject struct {
+	Id                string                 `json:"id"`
+	Type              string                 `json:"type"`
+	ConstructorParams map[string]interface{} `json:"constructorParams,omitempty"`
+	// Properties are the calls of the setters, e.g.
+	// "setMaxVideoRecvBandwidth", in call order. Only the last call is kept
+	// for each operation and objects given as parameters, e.g. for each
+	// port of setPortProperties.
+	Properties    []SnapshotProperty     `json:"properties,omitempty"`
+	Tags          map[string]string      `json:"tags,omitempty"`
+	Subscriptions []SnapshotSubscription `json:"subscriptions,omitempty"`
+}
+
+// SnapshotProperty is a call of a setter.
+type SnapshotProperty struct {
+	Operation string                 `json:"operation"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+}
+
+// SnapshotSubscription is a subscription to the events of an object.
+type SnapshotSubscription struct {
+	Event string `json:"event"`
+	Id    string `json:"id"`
+}
+
+// SnapshotConnection is a connection made by the connect operation of
+// Object, with the sink and media in Params.
+type SnapshotConnection struct {
+	Object string                 `json:"object"`
+	Params map[string]interface{} `json:"params"`
+}
+
+// Snapshot is the graph of the objects created through a connection, in
+// their order of creation. It can be stored with encoding/json.
+type Snapshot struct {
+	Objects     []SnapshotObject     `json:"objects"`
+	Connections []SnapshotConnection `json:"connections,omitempty"`
+}
+
+// Journal records the objects created through a connection, with their
+// properties, tags, connections and subscriptions, so the graph can be
+// rebuilt after a restart of KMS, or on another server.
+//
+// Only the requests made after NewJournal are known, and the negotiations
+// are not replayed: SDP, ICE candidates, and operations such as play or
+// record are left to the caller, which finds the new objects in the ID
+// mapping returned by Restore.
+type Journal struct {
+	lock        sync.Mutex
+	conn        *Connection
+	attached    map[*connState]bool
+	objects     []*SnapshotObject
+	connections []SnapshotConnection
+}
+
+// NewJournal returns a journal of the objects created through c, and
+// installs its interceptor on c.
+func NewJournal(c *Connection) *Journal {
+	j := &Journal{conn: c, attached: make(map[*connState]bool)}
+	j.attach(c)
+	return j
+}
+
+// attach installs the interceptor of the journal on c, once per connection.
+// It must be called with the lock held.
+func (j *Journal) attach(c *Connection) {
+	if c.connState == nil || j.attached[c.connState] {
+		return
+	}
+	j.attached[c.connState] = true
+	state := c.connState
+	c.Use(func(ctx context.Context, req map[string]interface{}, next Invoker) (Response, error) {
+		return j.intercept(state, ctx, req, next)
+	})
+}
+
+// intercept records the requests made through the connection followed by
+// the journal which change the graph, once they succeeded.
+func (j *Journal) intercept(state *connState, ctx context.Context, req map[string]interface{}, next Invoker) (Response, error) {
+	resp, err := next(ctx, req)
+	if err != nil || resp.Error != nil {
+		return resp, err
+	}
+	method, _ := req["method"].(string)
+	params, _ := req["params"].(map[string]interface{})
+	value, _ := resp.Result["value"].(string)
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if j.conn.connState != state {
+		return resp, nil
+	}
+
+	switch method {
+	case "create":
+		if value == "" {
+			break
+		}
+		typ, _ := params["type"].(string)
+		j.objects = append(j.objects, &SnapshotObject{
+			Id:                value,
+			Type:              typ,
+			ConstructorParams: normalize(params["constructorParams"]),
+		})
+	case "invoke":
+		object, _ := params["object"].(string)
+		operation, _ := params["operation"].(string)
+		j.invoked(object, operation, normalize(params["operationParams"]))
+	case "subscribe":
+		object, _ := params["object"].(string)
+		event, _ := params["type"].(string)
+		if o := j.object(object); o != nil && value != "" {
+			o.Subscriptions = append(o.Subscriptions, SnapshotSubscription{Event: event, Id: value})
+		}
+	case "unsubscribe":
+		object, _ := params["object"].(string)
+		id, _ := params["subscription"].(string)
+		if o := j.object(object); o != nil {
+			for i, s := range o.Subscriptions {
+				if s.Id == id {
+					o.Subscriptions = append(o.Subscriptions[:i], o.Subscriptions[i+1:]...)
+					break
+				}
+			}
+		}
+	case "release":
+		object, _ := params["object"].(string)
+		j.released(object)
+	}
+	return resp, nil
+}
+
+func (j *Journal) object(id string) *SnapshotObject {
+	for _, o := range j.objects {
+		if o.Id == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// isSetter tells if operation sets a property, e.g. "setMaxOutputBitrate".
+func isSetter(operation string) bool {
+	return len(operation) > 3 && strings.HasPrefix(operation, "set") && unicode.IsUpper(rune(operation[3]))
+}
+
+func (j *Journal) invoked(object, operation string, params map[string]interface{}) {
+	o := j.object(object)
+	if o == nil {
+		return
+	}
+	switch {
+	case operation == "addTag":
+		key, _ := params["key"].(string)
+		value, _ := params["value"].(string)
+		if o.Tags == nil {
+			o.Tags = make(map[string]string)
+		}
+		o.Tags[key] = value
+	case operation == "removeTag":
+		key, _ := params["key"].(string)
+		delete(o.Tags, key)
+	case operation == "connect":
+		j.connections = append(j.connections, SnapshotConnection{Object: object, Params: params})
+	case operation == "disconnect":
+		// the parameters left out of disconnect match every connection
+		kept := j.connections[:0]
+		for _, c := range j.connections {
+			match := c.Object == object
+			for k, v := range params {
+				if v, ok := v.(string); ok && v != "" && c.Params[k] != v {
+					match = false
+				}
+			}
+			if !match {
+				kept = append(kept, c)
+			}
+		}
+		j.connections = kept
+	case isSetter(operation):
+		targets := j.targets(params)
+		kept := o.Properties[:0]
+		for _, p := range o.Properties {
+			if p.Operation != operation || !reflect.DeepEqual(j.targets(p.Params), targets) {
+				kept = append(kept, p)
+			}
+		}
+		o.Properties = append(kept, SnapshotProperty{Operation: operation, Params: params})
+	}
+}
+
+// targets returns the parameters which are objects of the journal.
+func (j *Journal) targets(params map[string]interface{}) map[string]string {
+	var ret map[string]string
+	for k, v := range params {
+		if id, ok := v.(string); ok && j.object(id) != nil {
+			if ret == nil {
+				ret = make(map[string]string)
+			}
+			ret[k] = id
+		}
+	}
+	return ret
+}
+
+// refers tells if params refer to an object for which gone is true.
+func refers(params map[string]interface{}, gone func(string) bool) bool {
+	for _, v := range params {
+		if id, ok := v.(string); ok && gone(id) {
+			return true
+		}
+	}
+	return false
+}
+
+// released forgets object, its children, and the connections to them.
+func (j *Journal) released(object string) {
+	gone := func(id string) bool {
+		return id == object || strings.HasPrefix(id, object+"/")
+	}
+	kept := j.objects[:0]
+	for _, o := range j.objects {
+		if gone(o.Id) {
+			continue
+		}
+		// the setters given the object, e.g. setMaster
+		properties := o.Properties[:0]
+		for _, p := range o.Properties {
+			if !refers(p.Params, gone) {
+				properties = append(properties, p)
+			}
+		}
+		o.Properties = properties
+		kept = append(kept, o)
+	}
+	j.objects = kept
+
+	connections := j.connections[:0]
+	for _, c := range j.connections {
+		if !gone(c.Object) && !refers(c.Params, gone) {
+			connections = append(connections, c)
+		}
+	}
+	j.connections = connections
+}
+
+// normalize returns a copy of params made of JSON values only, which can
+// be stored and compared.
+func normalize(params interface{}) map[string]interface{} {
+	if params == nil {
+		return nil
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil
+	}
+	var ret map[string]interface{}
+	if json.Unmarshal(data, &ret) != nil || len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// Snapshot returns the graph of the objects known to the journal.
+func (j *Journal) Snapshot() *Snapshot {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	s := &Snapshot{}
+	for _, o := range j.objects {
+		s.Objects = append(s.Objects, *o)
+	}
+	s.Connections = append(s.Connections, j.connections...)
+
+	// deep copy, so later changes do not alter the snapshot
+	data, _ := json.Marshal(s)
+	ret := &Snapshot{}
+	json.Unmarshal(data, ret)
+	return ret
+}
+
+// Restore creates the objects of s on c, then sets their properties and
+// tags, connects them, and subscribes to their events. The handlers of the
+// subscriptions made through the connection of the journal are registered
+// again for the new objects, the others are left out.
+//
+// It returns the ID of the new object for the ID of every object of s
+// restored, even if it fails. Once it succeeds, the journal follows the
+// objects of c, and the handlers are removed from the previous connection.
+// If it fails, the journal keeps following the previous connection, and the
+// objects already created on c can be released with the returned IDs. The
+// requests made on c by others during Restore are not recorded.
+func (j *Journal) Restore(c *Connection, s *Snapshot) (map[string]string, error) {
+	j.lock.Lock()
+	old := j.conn
+	j.attach(c)
+	j.lock.Unlock()
+
+	ids := make(map[string]string)
+	for _, o := range s.Objects {
+		params := map[string]interface{}{
+			"type":              o.Type,
+			"constructorParams": remap(o.ConstructorParams, ids),
+		}
+		value, err := restoreRequest(c, "create", params)
+		if err != nil {
+			return ids, fmt.Errorf("kurento: restoring %s: %w", o.Id, err)
+		}
+		ids[o.Id] = value
+	}
+
+	for _, o := range s.Objects {
+		id := ids[o.Id]
+		for _, p := range o.Properties {
+			if err := restoreInvoke(c, id, p.Operation, remap(p.Params, ids)); err != nil {
+				return ids, fmt.Errorf("kurento: restoring %s of %s: %w", p.Operation, o.Id, err)
+			}
+		}
+
+		keys := make([]string, 0, len(o.Tags))
+		for key := range o.Tags {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			params := map[string]interface{}{"key": key, "value": o.Tags[key]}
+			if err := restoreInvoke(c, id, "addTag", params); err != nil {
+				return ids, fmt.Errorf("kurento: restoring tag %s of %s: %w", key, o.Id, err)
+			}
+		}
+	}
+
+	for _, conn := range s.Connections {
+		source, ok := ids[conn.Object]
+		if !ok {
+			return ids, fmt.Errorf("kurento: restoring connection of %s: unknown object", conn.Object)
+		}
+		if err := restoreInvoke(c, source, "connect", remap(conn.Params, ids)); err != nil {
+			return ids, fmt.Errorf("kurento: restoring connection of %s: %w", conn.Object, err)
+		}
+	}
+
+	// the state of the journal once restored, with the new IDs
+	objects := make([]*SnapshotObject, 0, len(s.Objects))
+	// the subscriptions whose handlers moved to c
+	type movedHandler struct{ event, object, id string }
+	var moved []movedHandler
+	for _, o := range s.Objects {
+		restored := &SnapshotObject{
+			Id:                ids[o.Id],
+			Type:              o.Type,
+			ConstructorParams: remap(o.ConstructorParams, ids),
+		}
+		for key, value := range o.Tags {
+			if restored.Tags == nil {
+				restored.Tags = make(map[string]string)
+			}
+			restored.Tags[key] = value
+		}
+		for _, p := range o.Properties {
+			restored.Properties = append(restored.Properties, SnapshotProperty{Operation: p.Operation, Params: remap(p.Params, ids)})
+		}
+		for _, sub := range o.Subscriptions {
+			handler := old.handler(sub.Event, o.Id, sub.Id)
+			if handler == nil {
+				continue
+			}
+			params := map[string]interface{}{"type": sub.Event, "object": ids[o.Id]}
+			handlerId, err := restoreRequest(c, "subscribe", params)
+			if err != nil {
+				return ids, fmt.Errorf("kurento: restoring subscription to %s of %s: %w", sub.Event, o.Id, err)
+			}
+			c.Subscribe(sub.Event, ids[o.Id], handlerId, handler)
+			restored.Subscriptions = append(restored.Subscriptions, SnapshotSubscription{Event: sub.Event, Id: handlerId})
+			moved = append(moved, movedHandler{sub.Event, o.Id, sub.Id})
+		}
+		objects = append(objects, restored)
+	}
+	connections := make([]SnapshotConnection, 0, len(s.Connections))
+	for _, conn := range s.Connections {
+		connections = append(connections, SnapshotConnection{Object: ids[conn.Object], Params: remap(conn.Params, ids)})
+	}
+
+	j.lock.Lock()
+	j.conn = c
+	j.objects = objects
+	j.connections = connections
+	j.lock.Unlock()
+
+	for _, h := range moved {
+		old.Unsubscribe(h.event, h.object, h.id)
+	}
+	return ids, nil
+}
+
+// handler returns the handler of a subscription, or nil.
+func (c *Connection) handler(event, objectId, handlerId string) eventHandler {
+	if c == nil || c.connState == nil {
+		return nil
+	}
+	c.events.lock.RLock()
+	defer c.events.lock.RUnlock()
+
+	return c.events.subscribers[event][objectId][handlerId]
+}
+
+// remap returns a copy of v where the IDs of ids are replaced.
+func remap[T any](v T, ids map[string]string) T {
+	var walk func(interface{}) interface{}
+	walk = func(v interface{}) interface{} {
+		switch v := v.(type) {
+		case string:
+			if id, ok := ids[v]; ok {
+				return id
+			}
+			return v
+		case map[string]interface{}:
+			if v == nil {
+				return v
+			}
+			ret := make(map[string]interface{}, len(v))
+			for k, e := range v {
+				ret[k] = walk(e)
+			}
+			return ret
+		case []interface{}:
+			ret := make([]interface{}, len(v))
+			for i, e := range v {
+				ret[i] = walk(e)
+			}
+			return ret
+		}
+		return v
+	}
+	ret, _ := walk(v).(T)
+	return ret
+}
+
+func restoreInvoke(c *Connection, object, operation string, params map[string]interface{}) error {
+	reqparams := map[string]interface{}{
+		"object":    object,
+		"operation": operation,
+	}
+	if params != nil {
+		reqparams["operationParams"] = params
+	}
+	_, err := restoreRequest(c, "invoke", reqparams)
+	return err
+}
+
+func restoreRequest(c *Connection, method string, params map[string]interface{}) (string, error) {
+	if sessionId := c.sessionId(); sessionId != "" {
+		params["sessionId"] = sessionId
+	}
+	req := map[string]interface{}{
+		"jsonrpc": "2.0",
+		"method":  method,
+		"params":  params,
+	}
+	res := <-c.Request(req)
+	if res.Error != nil {
+		return "", fmt.Errorf("[%d] %s %s", res.Error.Code, res.Error.Message, res.Error.Data)
+	}
+	value, _ := res.Result["value"].(string)
+	return value, nil
+}
+
+// Rebind points objects to their new ID on c, after a Restore.
+func Rebind(c *Connection, ids map[string]string, objects ...IMediaObject) {
+	for _, o := range objects {
+		if id, ok := ids[o.String()]; ok {
+			o.setConnection(c.base())
+			o.setId(id)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package kurento_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// buildGraph expects a pipeline with a player connected to a WebRTC
+// endpoint, whose IDs end with suffix. It stops at the request named fail,
+// which fails.
+func (s *script) buildGraph(suffix, fail string) {
+	pipeline, webRtc, player := "p"+suffix, "p"+suffix+"/w"+suffix, "p"+suffix+"/pl"+suffix
+	invoke := func(object, operation string, params map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"object": object, "operation": operation, "operationParams": params}
+	}
+	steps := []struct {
+		name   string
+		method string
+		params map[string]interface{}
+		value  interface{}
+	}{
+		{"createPipeline", "create", map[string]interface{}{"type": "MediaPipeline"}, pipeline},
+		{"createWebRtc", "create", map[string]interface{}{
+			"type":              "WebRtcEndpoint",
+			"constructorParams": map[string]interface{}{"mediaPipeline": pipeline},
+		}, webRtc},
+		{"createPlayer", "create", map[string]interface{}{
+			"type":              "PlayerEndpoint",
+			"constructorParams": map[string]interface{}{"mediaPipeline": pipeline, "uri": "file:///a.webm"},
+		}, player},
+		{"setter", "invoke", invoke(webRtc, "setMaxVideoRecvBandwidth", map[string]interface{}{"maxVideoRecvBandwidth": float64(500)}), nil},
+		{"tag", "invoke", invoke(webRtc, "addTag", map[string]interface{}{"key": "room", "value": "r1"}), nil},
+		{"connect", "invoke", invoke(player, "connect", map[string]interface{}{"sink": webRtc}), nil},
+		{"subscribe", "subscribe", map[string]interface{}{"object": player, "type": "EndOfStream"}, "h" + suffix},
+	}
+	for _, step := range steps {
+		if step.name == fail {
+			s.fail(step.method, step.params, 40101, "failed")
+			return
+		}
+		s.call(step.method, step.params, step.value)
+	}
+}
+
+func TestJournalRestore(t *testing.T) {
+	tests := []struct {
+		name string
+		fail string
+	}{
+		{"restored", ""},
+		{"create fails", "createPlayer"},
+		{"connect fails", "connect"},
+		{"subscribe fails", "subscribe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s1 := &script{}
+			s1.buildGraph("1", "")
+			// after Restore, to tell which handlers are left on conn1
+			s1.invoke("p1", "getName", "p1")
+			s1.event("p1/pl1", "EndOfStream", nil)
+			s1.event("p1/pl1", "Marker", nil)
+			conn1, srv1 := s1.serve(t)
+
+			journal := kurento.NewJournal(conn1)
+			pipeline := &kurento.MediaPipeline{}
+			webRtc := &kurento.WebRtcEndpoint{}
+			player := &kurento.PlayerEndpoint{}
+			ends := make(chan string, 4)
+			for _, err := range []error{
+				conn1.Create(pipeline, nil),
+				pipeline.Create(webRtc, nil),
+				pipeline.Create(player, kurento.PlayerEndpointOptions{Uri: "file:///a.webm"}.ConstructorParams()),
+				webRtc.SetMaxVideoRecvBandwidth(500),
+				webRtc.AddTag("room", "r1"),
+				player.Connect(webRtc, "", "", ""),
+			} {
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if _, err := player.SubscribeEndOfStream(func(ev kurento.EndOfStreamEvent) { ends <- ev.Source }); err != nil {
+				t.Fatal(err)
+			}
+
+			before := journal.Snapshot()
+			want := &kurento.Snapshot{
+				Objects: []kurento.SnapshotObject{
+					{Id: "p1", Type: "MediaPipeline"},
+					{
+						Id: "p1/w1", Type: "WebRtcEndpoint",
+						ConstructorParams: before.Objects[1].ConstructorParams,
+						Properties: []kurento.SnapshotProperty{
+							{Operation: "setMaxVideoRecvBandwidth", Params: map[string]interface{}{"maxVideoRecvBandwidth": float64(500)}},
+						},
+						Tags: map[string]string{"room": "r1"},
+					},
+					{
+						Id: "p1/pl1", Type: "PlayerEndpoint",
+						ConstructorParams: before.Objects[2].ConstructorParams,
+						Subscriptions:     []kurento.SnapshotSubscription{{Event: "EndOfStream", Id: "h1"}},
+					},
+				},
+				Connections: []kurento.SnapshotConnection{{Object: "p1/pl1", Params: before.Connections[0].Params}},
+			}
+			if !reflect.DeepEqual(before, want) {
+				t.Fatalf("snapshot = %+v, want %+v", before, want)
+			}
+
+			s2 := &script{}
+			s2.buildGraph("2", tt.fail)
+			if tt.fail == "" {
+				// once the handler is registered again
+				s2.delay(50 * time.Millisecond)
+				s2.event("p2/pl2", "EndOfStream", nil)
+			}
+			conn2, srv2 := s2.serve(t)
+
+			ids, err := journal.Restore(conn2, before)
+			if (err != nil) != (tt.fail != "") {
+				t.Fatalf("Restore() = %v", err)
+			}
+			done(t, srv2)
+
+			after := journal.Snapshot()
+			if tt.fail != "" {
+				if !reflect.DeepEqual(after, before) {
+					t.Errorf("snapshot after a failure = %+v, want %+v", after, before)
+				}
+			} else {
+				if got := wait(t, ends); got != "p2/pl2" {
+					t.Errorf("event of %s, want p2/pl2", got)
+				}
+				if len(after.Objects) != 3 || after.Objects[2].Id != "p2/pl2" ||
+					after.Objects[2].Subscriptions[0].Id != "h2" ||
+					after.Objects[1].ConstructorParams["mediaPipeline"] != "p2" ||
+					after.Connections[0].Params["sink"] != "p2/w2" {
+					t.Errorf("snapshot after Restore = %+v", after)
+				}
+			}
+			for old, id := range ids {
+				if strings.ReplaceAll(old, "1", "2") != id {
+					t.Errorf("%s restored as %s", old, id)
+				}
+			}
+
+			// the handler is left on conn1 only if Restore failed
+			marker := make(chan bool)
+			conn1.Subscribe("Marker", "p1/pl1", "marker", func(map[string]interface{}) { marker <- true })
+			if _, err := pipeline.GetName(); err != nil {
+				t.Fatal(err)
+			}
+			wait(t, marker)
+			done(t, srv1)
+			select {
+			case source := <-ends:
+				if tt.fail == "" {
+					t.Errorf("event of %s delivered to the previous connection", source)
+				}
+			default:
+				if tt.fail != "" {
+					t.Error("handler removed from the previous connection")
+				}
+			}
+		})
+	}
+}
+
+func TestJournalSetters(t *testing.T) {
+	s := &script{}
+	s.create("MediaPipeline", "p")
+	s.create("AlphaBlending", "p/a")
+	s.create("HubPort", "p/a/1")
+	s.create("HubPort", "p/a/2")
+	for range 6 {
+		s.call("invoke", map[string]interface{}{"object": "p/a"}, nil)
+	}
+	s.release("p/a/2")
+	conn, srv := s.serve(t)
+
+	journal := kurento.NewJournal(conn)
+	pipeline := &kurento.MediaPipeline{}
+	hub := &kurento.AlphaBlending{}
+	port1, port2 := &kurento.HubPort{}, &kurento.HubPort{}
+	for _, err := range []error{
+		conn.Create(pipeline, nil),
+		pipeline.Create(hub, nil),
+		hub.Create(port1, nil),
+		hub.Create(port2, nil),
+		hub.SetPortProperties(0, 0, 1, 0.5, 0.5, port1),
+		hub.SetPortProperties(0.5, 0, 2, 0.5, 0.5, port2),
+		hub.SetMaster(port1, 0),
+		hub.SetMaster(port2, 1),
+		// the last call for each port is kept
+		hub.SetPortProperties(0, 0.5, 3, 0.5, 0.5, port1),
+		hub.SetMaster(port1, 4),
+		// the setters given a released object are dropped
+		port2.Release(),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	done(t, srv)
+
+	var got []string
+	for _, p := range journal.Snapshot().Objects[1].Properties {
+		got = append(got, fmt.Sprintf("%s %v %v %v", p.Operation, p.Params["port"], p.Params["source"], p.Params["zOrder"]))
+	}
+	want := []string{"setPortProperties p/a/1 <nil> 3", "setMaster <nil> p/a/1 4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("properties = %q, want %q", got, want)
+	}
+}
+
+func TestRemap(t *testing.T) {
+	ids := map[string]string{"p1": "p2", "p1/w1": "p2/w2"}
+	tests := []struct {
+		name string
+		in   map[string]interface{}
+		want map[string]interface{}
+	}{
+		{"nil", nil, nil},
+		{"ids", map[string]interface{}{"sink": "p1/w1", "mediaType": "VIDEO"}, map[string]interface{}{"sink": "p2/w2", "mediaType": "VIDEO"}},
+		{"nested", map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{"p1", 3.0}}}, map[string]interface{}{"a": map[string]interface{}{"b": []interface{}{"p2", 3.0}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := kurento.Remap(tt.in, ids); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("remap = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package kurento
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// MasterKeyLength returns the length in bytes of the master key and salt of
+// the suite: 30 for the AES_128 suites, 46 for the AES_256 ones, or 0 for an
+// unknown suite.
+func (t CryptoSuite) MasterKeyLength() int {
+	switch {
+	case strings.HasPrefix(string(t), "AES_128_"):
+		return 16 + 14
+	case strings.HasPrefix(string(t), "AES_256_"):
+		return 32 + 14
+	}
+	return 0
+}
+
+// NewSDES returns the SRTP configuration of suite with a random master key,
+// to be given to the RtpEndpoint and to its peer.
+func NewSDES(suite CryptoSuite) (SDES, error) {
+	n := suite.MasterKeyLength()
+	if n == 0 {
+		return SDES{}, fmt.Errorf("kurento: unknown crypto suite %q", suite)
+	}
+	key := make([]byte, n)
+	if _, err := rand.Read(key); err != nil {
+		return SDES{}, err
+	}
+	return SDES{KeyBase64: base64.StdEncoding.EncodeToString(key), Crypto: suite}, nil
+}
+
+// Validate checks that the master key, given either as Key or KeyBase64, has
+// the length required by the crypto suite.
+func (t SDES) Validate() error {
+	n := t.Crypto.MasterKeyLength()
+	if n == 0 {
+		return fmt.Errorf("kurento: unknown crypto suite %q", t.Crypto)
+	}
+	switch {
+	case t.Key != "" && t.KeyBase64 != "":
+		return fmt.Errorf("kurento: only one of key and keyBase64 must be set")
+	case t.Key != "":
+		if len(t.Key) != n {
+			return fmt.Errorf("kurento: %s requires a key of %d bytes, got %d", t.Crypto, n, len(t.Key))
+		}
+	case t.KeyBase64 != "":
+		key, err := base64.StdEncoding.DecodeString(t.KeyBase64)
+		if err != nil {
+			return fmt.Errorf("kurento: invalid keyBase64: %w", err)
+		}
+		if len(key) != n {
+			return fmt.Errorf("kurento: %s requires a key of %d bytes, got %d", t.Crypto, n, len(key))
+		}
+	}
+	return nil
+}
+
+// NewRtpEndpoint creates an RtpEndpoint in pipeline. The crypto options, if
+// any, are validated first.
+func NewRtpEndpoint(pipeline *MediaPipeline, options RtpEndpointOptions) (*RtpEndpoint, error) {
+	if options.Crypto != nil {
+		if err := options.Crypto.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	ep := &RtpEndpoint{}
+	if err := pipeline.Create(ep, options.ConstructorParams()); err != nil {
+		return nil, err
+	}
+	return ep, nil
+}
+
+// GenerateRtpOffer generates an offer for an RTP peer such as a SIP gateway,
+// with only the requested media.
+func (elem *RtpEndpoint) GenerateRtpOffer(audio, video bool) (string, error) {
+	return elem.GenerateOffer(OfferOptions{OfferToReceiveAudio: audio, OfferToReceiveVideo: video})
+}
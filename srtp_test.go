@@ -0,0 +1,100 @@
+package kurento_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestNewSDES(t *testing.T) {
+	tests := []struct {
+		suite kurento.CryptoSuite
+		want  int
+	}{
+		{kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_32, 30},
+		{kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80, 30},
+		{kurento.CRYPTOSUITE_AES_256_CM_HMAC_SHA1_32, 46},
+		{kurento.CRYPTOSUITE_AES_256_CM_HMAC_SHA1_80, 46},
+		{"AES_192_CM_HMAC_SHA1_80", 0},
+	}
+	for _, tt := range tests {
+		sdes, err := kurento.NewSDES(tt.suite)
+		if tt.want == 0 {
+			if err == nil {
+				t.Errorf("%s: key generated", tt.suite)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %v", tt.suite, err)
+		}
+		key, err := base64.StdEncoding.DecodeString(sdes.KeyBase64)
+		if err != nil || len(key) != tt.want || sdes.Crypto != tt.suite || sdes.Key != "" {
+			t.Errorf("%s: SDES %+v", tt.suite, sdes)
+		}
+		if err := sdes.Validate(); err != nil {
+			t.Errorf("%s: %v", tt.suite, err)
+		}
+	}
+}
+
+func TestSDESValidate(t *testing.T) {
+	key30 := strings.Repeat("k", 30)
+	tests := []struct {
+		name    string
+		sdes    kurento.SDES
+		wantErr bool
+	}{
+		{"key", kurento.SDES{Key: key30, Crypto: kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80}, false},
+		{"base64 key", kurento.SDES{KeyBase64: base64.StdEncoding.EncodeToString([]byte(key30)), Crypto: kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_32}, false},
+		{"short key", kurento.SDES{Key: key30, Crypto: kurento.CRYPTOSUITE_AES_256_CM_HMAC_SHA1_80}, true},
+		{"short base64 key", kurento.SDES{KeyBase64: "a2V5", Crypto: kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80}, true},
+		{"invalid base64", kurento.SDES{KeyBase64: "not base64!", Crypto: kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80}, true},
+		{"both keys", kurento.SDES{Key: key30, KeyBase64: base64.StdEncoding.EncodeToString([]byte(key30)), Crypto: kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80}, true},
+		{"unknown suite", kurento.SDES{Key: key30}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.sdes.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: err = %v, want error %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestNewRtpEndpoint(t *testing.T) {
+	sdes, err := kurento.NewSDES(kurento.CRYPTOSUITE_AES_128_CM_HMAC_SHA1_80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &script{}
+	s.call("create", map[string]interface{}{
+		"type": "RtpEndpoint",
+		"constructorParams": map[string]interface{}{
+			"mediaPipeline": "pipe",
+			"crypto":        map[string]interface{}{"keyBase64": sdes.KeyBase64, "crypto": "AES_128_CM_HMAC_SHA1_80", "__type__": "SDES"},
+			"useIpv6":       true,
+		},
+	}, "pipe/rtp")
+	s.call("invoke", map[string]interface{}{
+		"object":          "pipe/rtp",
+		"operation":       "generateOffer",
+		"operationParams": map[string]interface{}{"options": map[string]interface{}{"offerToReceiveAudio": true, "offerToReceiveVideo": false}},
+	}, "offer")
+	conn, srv := s.serve(t)
+
+	pipeline := &kurento.MediaPipeline{}
+	kurento.HydrateMediaObject("pipe", nil, conn, pipeline)
+	// rejected before any request
+	if _, err := kurento.NewRtpEndpoint(pipeline, kurento.RtpEndpointOptions{Crypto: &kurento.SDES{Key: "short"}}); err == nil {
+		t.Fatal("invalid crypto accepted")
+	}
+	ep, err := kurento.NewRtpEndpoint(pipeline, kurento.RtpEndpointOptions{Crypto: &sdes, UseIpv6: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offer, err := ep.GenerateRtpOffer(true, false); err != nil || offer != "offer" {
+		t.Errorf("GenerateRtpOffer() = %q, %v", offer, err)
+	}
+	done(t, srv)
+}
@@ -0,0 +1,77 @@
+package kurento
+
+import (
+	"fmt"
+	"sort"
+)
+
+// StatsReport is a stats report with every member of its stats objects, by
+// stats ID. GetStats only decodes the members common to all stats.
+type StatsReport map[string]map[string]interface{}
+
+// GetStatsReport is GetStats returning the full report. If no media type is
+// specified, it returns statistics for all available types.
+func (elem *MediaElement) GetStatsReport(mediaType MediaType) (StatsReport, error) {
+	req := elem.getInvokeRequest()
+
+	params := make(map[string]interface{})
+	setIfNotEmpty(params, "mediaType", mediaType)
+
+	reqparams := map[string]interface{}{
+		"operation":       "getStats",
+		"object":          elem.Id,
+		"operationParams": params,
+	}
+	if sessionId := elem.connection.sessionId(); sessionId != "" {
+		reqparams["sessionId"] = sessionId
+	}
+	req["params"] = reqparams
+
+	response := <-elem.connection.Request(req)
+	if response.Error != nil {
+		return nil, fmt.Errorf("[%d] %s %s", response.Error.Code, response.Error.Message, response.Error.Data)
+	}
+
+	ret := StatsReport{}
+	if value, ok := response.Result["value"]; ok {
+		if err := decodeValue(elem.connection, value, &ret); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+// IdsOfType returns the IDs of the stats of type t, sorted.
+func (r StatsReport) IdsOfType(t StatsType) []string {
+	var ret []string
+	for id, stats := range r {
+		if stats["type"] == string(t) {
+			ret = append(ret, id)
+		}
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// Decode fills dst, a pointer to one of the RTC*Stats or *Stats types, with
+// the stats of the given ID.
+func (r StatsReport) Decode(id string, dst interface{}) error {
+	stats, ok := r[id]
+	if !ok {
+		return fmt.Errorf("kurento: no stats %q in the report", id)
+	}
+	return decodeValue(nil, stats, dst)
+}
+
+// DataChannels returns the stats of the data channels of the report, sorted
+// by ID.
+func (r StatsReport) DataChannels() []RTCDataChannelStats {
+	var ret []RTCDataChannelStats
+	for _, id := range r.IdsOfType(STATSTYPE_datachannel) {
+		var stats RTCDataChannelStats
+		if err := r.Decode(id, &stats); err == nil {
+			ret = append(ret, stats)
+		}
+	}
+	return ret
+}
@@ -0,0 +1,64 @@
+package kurento_test
+
+import (
+	"fmt"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestStatsReport(t *testing.T) {
+	s := &script{}
+	s.call("invoke", map[string]interface{}{
+		"object":          "pipe/webrtc",
+		"operation":       "getStats",
+		"operationParams": map[string]interface{}{"mediaType": "VIDEO"},
+	}, map[string]interface{}{
+		"dc2":  map[string]interface{}{"type": "datachannel", "label": "files", "datachannelid": 2, "bytesSent": 512},
+		"dc1":  map[string]interface{}{"type": "datachannel", "label": "chat", "datachannelid": 1, "state": "open"},
+		"in1":  map[string]interface{}{"type": "inboundrtp", "packetsReceived": 3},
+		"bad1": map[string]interface{}{"type": "datachannel", "datachannelid": "not a number"},
+	})
+	s.fail("invoke", map[string]interface{}{"object": "pipe/webrtc", "operation": "getStats"}, 40101, "failed")
+	conn, srv := s.serve(t)
+
+	endpoint := &kurento.WebRtcEndpoint{}
+	kurento.HydrateMediaObject("pipe/webrtc", nil, conn, endpoint)
+	report, err := endpoint.GetStatsReport(kurento.MEDIATYPE_VIDEO)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		typ  kurento.StatsType
+		want string
+	}{
+		{kurento.STATSTYPE_datachannel, "[bad1 dc1 dc2]"},
+		{kurento.STATSTYPE_inboundrtp, "[in1]"},
+		{kurento.STATSTYPE_outboundrtp, "[]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(report.IdsOfType(tt.typ)); got != tt.want {
+			t.Errorf("IdsOfType(%s) = %s, want %s", tt.typ, got, tt.want)
+		}
+	}
+
+	// the stats which cannot be decoded are left out
+	channels := report.DataChannels()
+	if len(channels) != 2 || channels[0].Label != "chat" || channels[0].State != "open" ||
+		channels[1].Datachannelid != 2 || channels[1].BytesSent != 512 {
+		t.Errorf("DataChannels() = %+v", channels)
+	}
+	var inbound kurento.RTCInboundRTPStreamStats
+	if err := report.Decode("in1", &inbound); err != nil || inbound.PacketsReceived != 3 {
+		t.Errorf("Decode() = %+v, %v", inbound, err)
+	}
+	if err := report.Decode("out1", &inbound); err == nil {
+		t.Error("missing stats decoded")
+	}
+
+	if _, err := endpoint.GetStatsReport(""); err == nil {
+		t.Error("error of KMS ignored")
+	}
+	done(t, srv)
+}
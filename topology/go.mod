@@ -0,0 +1,15 @@
+module github.com/safermobility/kurento-go/v6/topology
+
+go 1.24
+
+require (
+	github.com/safermobility/kurento-go/v6 v6.0.0-20261018133638-5dadf9de3334
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/rogpeppe/go-internal v1.10.0 // indirect
+	golang.org/x/net v0.37.0 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
+)
@@ -0,0 +1,256 @@
+// Package topology describes a media pipeline declaratively, as a list of
+// elements and the connections between them, and builds it on a Kurento
+// server.
+//
+// A topology can be written in Go or loaded from YAML or JSON:
+//
+//	elements:
+//	  - name: presenter
+//	    type: WebRtcEndpoint
+//	  - name: recorder
+//	    type: RecorderEndpoint
+//	    options:
+//	      uri: file:///tmp/presenter.webm
+//	connections:
+//	  - from: presenter
+//	    to: recorder
+//	    media: [AUDIO, VIDEO]
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"gopkg.in/yaml.v3"
+)
+
+// Element is an object to create in the pipeline.
+type Element struct {
+	// Name identifies the element in connections and in the built pipeline.
+	Name string `json:"name" yaml:"name"`
+
+	// Type is the Kurento type of the element, e.g. "WebRtcEndpoint".
+	Type string `json:"type" yaml:"type"`
+
+	// Parent is the name of the hub a HubPort is created from. Other elements
+	// are created from the pipeline.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+
+	// Options are the constructor parameters of the element.
+	Options map[string]interface{} `json:"options,omitempty" yaml:"options,omitempty"`
+
+	// Typed are constructor parameters merged over Options, for topologies
+	// written in Go.
+	Typed kurento.ConstructorOptions `json:"-" yaml:"-"`
+}
+
+// Connection connects two elements, media flowing from From to To.
+type Connection struct {
+	From string `json:"from" yaml:"from"`
+	To   string `json:"to" yaml:"to"`
+
+	// Media are the media types to connect. All media are connected if empty.
+	Media []kurento.MediaType `json:"media,omitempty" yaml:"media,omitempty"`
+
+	SourceDescription string `json:"sourceDescription,omitempty" yaml:"sourceDescription,omitempty"`
+	SinkDescription   string `json:"sinkDescription,omitempty" yaml:"sinkDescription,omitempty"`
+}
+
+// Topology is the description of a pipeline. Elements are created in order,
+// so a hub must be declared before its ports.
+type Topology struct {
+	Elements    []Element    `json:"elements" yaml:"elements"`
+	Connections []Connection `json:"connections,omitempty" yaml:"connections,omitempty"`
+}
+
+// ParseJSON reads a topology written in JSON.
+func ParseJSON(data []byte) (*Topology, error) {
+	t := &Topology{}
+	if err := json.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, t.Validate()
+}
+
+// ParseYAML reads a topology written in YAML.
+func ParseYAML(data []byte) (*Topology, error) {
+	t := &Topology{}
+	if err := yaml.Unmarshal(data, t); err != nil {
+		return nil, err
+	}
+	return t, t.Validate()
+}
+
+// Validate checks that types are known, names are unique and that
+// connections and parents refer to declared elements of the right kind.
+func (t *Topology) Validate() error {
+	declared := make(map[string]kurento.IMediaObject)
+	for i, e := range t.Elements {
+		if e.Name == "" {
+			return fmt.Errorf("topology: element %d has no name", i)
+		}
+		if _, ok := declared[e.Name]; ok {
+			return fmt.Errorf("topology: element %q declared twice", e.Name)
+		}
+		obj := kurento.NewMediaObject(e.Type)
+		if obj == nil {
+			return fmt.Errorf("topology: element %q: unknown type %q", e.Name, e.Type)
+		}
+		if _, ok := obj.(*kurento.MediaPipeline); ok {
+			return fmt.Errorf("topology: element %q: the pipeline is implicit", e.Name)
+		}
+		if _, isPort := obj.(*kurento.HubPort); isPort != (e.Parent != "") {
+			return fmt.Errorf("topology: element %q: a parent hub is required for, and only for, HubPort", e.Name)
+		}
+		if e.Parent != "" {
+			parent, ok := declared[e.Parent]
+			if !ok {
+				return fmt.Errorf("topology: element %q: parent %q must be declared before", e.Name, e.Parent)
+			}
+			// hubs are the only objects, besides the pipeline, which are not elements
+			if _, ok := parent.(kurento.IMediaElement); ok {
+				return fmt.Errorf("topology: element %q: parent %q is not a hub", e.Name, e.Parent)
+			}
+		}
+		declared[e.Name] = obj
+	}
+
+	for i, c := range t.Connections {
+		for _, name := range []string{c.From, c.To} {
+			obj, ok := declared[name]
+			if !ok {
+				return fmt.Errorf("topology: connection %d: unknown element %q", i, name)
+			}
+			if _, ok := obj.(kurento.IMediaElement); !ok {
+				return fmt.Errorf("topology: connection %d: %q is not a media element", i, name)
+			}
+		}
+		for _, m := range c.Media {
+			switch m {
+			case kurento.MEDIATYPE_AUDIO, kurento.MEDIATYPE_VIDEO, kurento.MEDIATYPE_DATA:
+			default:
+				return fmt.Errorf("topology: connection %d: unknown media type %q", i, m)
+			}
+		}
+	}
+	return nil
+}
+
+// Pipeline is a built topology.
+type Pipeline struct {
+	Pipeline *kurento.MediaPipeline
+
+	// Elements are the created objects, by name.
+	Elements map[string]kurento.IMediaObject
+}
+
+// Element returns the media element of the given name, or nil.
+func (p *Pipeline) Element(name string) kurento.IMediaElement {
+	e, _ := p.Elements[name].(kurento.IMediaElement)
+	return e
+}
+
+// Release releases the pipeline, and all its elements.
+func (p *Pipeline) Release() error {
+	return p.Pipeline.Release()
+}
+
+// Build creates a new pipeline on conn holding the topology. If a step fails
+// or ctx is done, the pipeline is released and the error returned. The
+// requests are made with ctx, but the built objects are bound to conn.
+func (t *Topology) Build(ctx context.Context, conn *kurento.Connection) (*Pipeline, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	pipeline := &kurento.MediaPipeline{}
+	if err := conn.WithContext(ctx).Create(pipeline, nil); err != nil {
+		return nil, err
+	}
+
+	built, err := t.build(ctx, pipeline)
+	if err != nil {
+		if rerr := pipeline.Release(); rerr != nil {
+			err = errors.Join(err, rerr)
+		}
+		return nil, err
+	}
+	return built, nil
+}
+
+// BuildIn creates the topology in an existing pipeline. If a step fails or
+// ctx is done, the elements created so far are released, in reverse order.
+func (t *Topology) BuildIn(ctx context.Context, pipeline *kurento.MediaPipeline) (*Pipeline, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t.build(ctx, pipeline)
+}
+
+func (t *Topology) build(ctx context.Context, pipeline *kurento.MediaPipeline) (ret *Pipeline, err error) {
+	built := &Pipeline{
+		Pipeline: pipeline,
+		Elements: make(map[string]kurento.IMediaObject),
+	}
+	var created []kurento.IMediaObject
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(created) - 1; i >= 0; i-- {
+			if rerr := created[i].Release(); rerr != nil {
+				err = errors.Join(err, rerr)
+			}
+		}
+	}()
+
+	for _, e := range t.Elements {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		var from kurento.IMediaObject = pipeline
+		if e.Parent != "" {
+			from = built.Elements[e.Parent]
+		}
+		// the created object is bound to the connection of from, not ctx
+		obj := kurento.NewMediaObject(e.Type)
+		if err := kurento.WithContext(ctx, from).Create(obj, e.options()); err != nil {
+			return nil, fmt.Errorf("topology: create %q: %w", e.Name, err)
+		}
+		created = append(created, obj)
+		built.Elements[e.Name] = obj
+	}
+
+	for _, c := range t.Connections {
+		source := kurento.WithContext(ctx, built.Elements[c.From]).(kurento.IMediaElement)
+		sink := built.Element(c.To)
+		media := c.Media
+		if len(media) == 0 {
+			media = []kurento.MediaType{""}
+		}
+		for _, m := range media {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if err := source.Connect(sink, m, c.SourceDescription, c.SinkDescription); err != nil {
+				return nil, fmt.Errorf("topology: connect %q to %q: %w", c.From, c.To, err)
+			}
+		}
+	}
+	return built, nil
+}
+
+func (e Element) options() map[string]interface{} {
+	ret := make(map[string]interface{}, len(e.Options))
+	for k, v := range e.Options {
+		ret[k] = v
+	}
+	if e.Typed != nil {
+		for k, v := range e.Typed.ConstructorParams() {
+			ret[k] = v
+		}
+	}
+	return ret
+}
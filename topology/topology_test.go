@@ -0,0 +1,297 @@
+package topology
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"github.com/safermobility/kurento-go/v6/replay"
+)
+
+const sample = `
+elements:
+  - name: mixer
+    type: Composite
+  - name: port
+    type: HubPort
+    parent: mixer
+  - name: presenter
+    type: WebRtcEndpoint
+  - name: recorder
+    type: RecorderEndpoint
+    options:
+      uri: file:///tmp/presenter.webm
+connections:
+  - from: presenter
+    to: recorder
+    media: [AUDIO, VIDEO]
+  - from: presenter
+    to: port
+`
+
+func TestParse(t *testing.T) {
+	y, err := ParseYAML([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := ParseJSON(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(y, j) {
+		t.Errorf("JSON topology %+v, want %+v", j, y)
+	}
+	if len(y.Elements) != 4 || y.Elements[3].Options["uri"] != "file:///tmp/presenter.webm" ||
+		len(y.Connections[0].Media) != 2 || y.Connections[1].Media != nil {
+		t.Errorf("topology = %+v", y)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"no name", "elements: [{type: WebRtcEndpoint}]", "no name"},
+		{"declared twice", "elements: [{name: a, type: WebRtcEndpoint}, {name: a, type: PlayerEndpoint}]", "declared twice"},
+		{"unknown type", "elements: [{name: a, type: Nope}]", "unknown type"},
+		{"pipeline", "elements: [{name: a, type: MediaPipeline}]", "implicit"},
+		{"port without hub", "elements: [{name: a, type: HubPort}]", "parent hub"},
+		{"parent of an endpoint", "elements: [{name: m, type: Composite}, {name: a, type: WebRtcEndpoint, parent: m}]", "parent hub"},
+		{"parent declared after", "elements: [{name: a, type: HubPort, parent: m}, {name: m, type: Composite}]", "declared before"},
+		{"parent not a hub", "elements: [{name: m, type: WebRtcEndpoint}, {name: a, type: HubPort, parent: m}]", "not a hub"},
+		{"unknown element", "elements: [{name: a, type: WebRtcEndpoint}]\nconnections: [{from: a, to: b}]", "unknown element"},
+		{"hub connected", "elements: [{name: a, type: WebRtcEndpoint}, {name: m, type: Composite}]\nconnections: [{from: a, to: m}]", "not a media element"},
+		{"unknown media", "elements: [{name: a, type: WebRtcEndpoint}]\nconnections: [{from: a, to: a, media: [SOUND]}]", "unknown media type"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseYAML([]byte(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// request is a request expected by a fake server, and its result. The
+// request fails if value is an error.
+type request struct {
+	method string
+	params map[string]interface{}
+	value  interface{}
+}
+
+// serve starts a fake server answering requests in order, and returns a
+// connection to it.
+func serve(t *testing.T, requests []request) (*kurento.Connection, *replay.Server) {
+	t.Helper()
+
+	var entries []replay.Entry
+	add := func(dir string, message map[string]interface{}) {
+		data, err := json.Marshal(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, replay.Entry{Dir: dir, Message: data})
+	}
+	for i, r := range requests {
+		add(replay.Sent, map[string]interface{}{"method": r.method, "params": r.params})
+		response := map[string]interface{}{"jsonrpc": "2.0", "id": i + 1}
+		if err, ok := r.value.(error); ok {
+			response["error"] = map[string]interface{}{"code": 40101, "message": err.Error()}
+		} else {
+			response["result"] = map[string]interface{}{"value": r.value}
+		}
+		add(replay.Received, response)
+	}
+	srv := replay.NewServer(entries, replay.ServerOptions{Match: subset})
+	ts := httptest.NewServer(srv)
+	conn, err := kurento.NewConnection("ws" + strings.TrimPrefix(ts.URL, "http"))
+	if err != nil {
+		ts.Close()
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		ts.Close()
+	})
+	return conn, srv
+}
+
+// subset tells if the members of expected are in got.
+func subset(expected, got map[string]interface{}) bool {
+	for k, v := range expected {
+		e, isMap := v.(map[string]interface{})
+		g, gotMap := got[k].(map[string]interface{})
+		switch {
+		case isMap && gotMap:
+			if !subset(e, g) {
+				return false
+			}
+		case !reflect.DeepEqual(v, got[k]):
+			return false
+		}
+	}
+	return true
+}
+
+func create(typ, id string, params map[string]interface{}) request {
+	return request{"create", map[string]interface{}{"type": typ, "constructorParams": params}, id}
+}
+
+func connect(source, sink, media string) request {
+	params := map[string]interface{}{"sink": sink}
+	if media != "" {
+		params["mediaType"] = media
+	}
+	return request{"invoke", map[string]interface{}{"object": source, "operation": "connect", "operationParams": params}, nil}
+}
+
+func release(id string) request {
+	return request{"release", map[string]interface{}{"object": id}, nil}
+}
+
+func TestBuild(t *testing.T) {
+	failed := &kurento.Error{Code: 40101, Message: "failed"}
+	elements := []request{
+		create("Composite", "p/mixer", map[string]interface{}{"mediaPipeline": "p"}),
+		create("HubPort", "p/port", map[string]interface{}{"hub": "p/mixer"}),
+		create("WebRtcEndpoint", "p/presenter", map[string]interface{}{"mediaPipeline": "p"}),
+		create("RecorderEndpoint", "p/recorder", map[string]interface{}{"mediaPipeline": "p", "uri": "file:///tmp/presenter.webm"}),
+	}
+	connections := []request{
+		connect("p/presenter", "p/recorder", "AUDIO"),
+		connect("p/presenter", "p/recorder", "VIDEO"),
+		connect("p/presenter", "p/port", ""),
+	}
+	fail := func(r request) request {
+		r.value = failed
+		return r
+	}
+	pipeline := create("MediaPipeline", "p", nil)
+
+	tests := []struct {
+		name     string
+		requests []request
+		wantErr  bool
+	}{
+		{"built", append(append([]request{pipeline}, elements...), connections...), false},
+		{
+			name: "create fails",
+			requests: append([]request{pipeline}, elements[0], elements[1], fail(elements[2]),
+				release("p/port"), release("p/mixer"), release("p")),
+			wantErr: true,
+		},
+		{
+			name: "connect fails",
+			requests: append(append([]request{pipeline}, elements...), connections[0], fail(connections[1]),
+				release("p/recorder"), release("p/presenter"), release("p/port"), release("p/mixer"), release("p")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			topology, err := ParseYAML([]byte(sample))
+			if err != nil {
+				t.Fatal(err)
+			}
+			conn, srv := serve(t, tt.requests)
+			built, err := topology.Build(context.Background(), conn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Build() = %v", err)
+			}
+			select {
+			case <-srv.Done():
+			case <-time.After(5 * time.Second):
+				t.Fatal("transcript not replayed")
+			}
+			if err := srv.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if built.Pipeline.Id != "p" || len(built.Elements) != 4 {
+				t.Errorf("built %+v", built)
+			}
+			if e := built.Element("presenter"); e == nil || e.(*kurento.WebRtcEndpoint).Id != "p/presenter" {
+				t.Errorf("presenter = %v", e)
+			}
+			// not a media element
+			if built.Element("mixer") != nil {
+				t.Error("mixer is a media element")
+			}
+		})
+	}
+}
+
+func TestBuildCanceled(t *testing.T) {
+	topology, err := ParseYAML([]byte(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, srv := serve(t, []request{release("p")})
+	pipeline := &kurento.MediaPipeline{}
+	kurento.HydrateMediaObject("p", nil, conn, pipeline)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := topology.BuildIn(ctx, pipeline); err != context.Canceled {
+		t.Fatalf("BuildIn() = %v", err)
+	}
+	// nothing was created
+	if err := pipeline.Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type ctxKey struct{}
+
+func TestBuildContext(t *testing.T) {
+	topology, err := ParseYAML([]byte("elements: [{name: a, type: WebRtcEndpoint}]\nconnections: [{from: a, to: a}]"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, srv := serve(t, []request{
+		create("MediaPipeline", "p", nil),
+		create("WebRtcEndpoint", "p/a", map[string]interface{}{"mediaPipeline": "p"}),
+		connect("p/a", "p/a", ""),
+		release("p/a"),
+	})
+	var withCtx []bool
+	conn.Use(func(ctx context.Context, req map[string]interface{}, next kurento.Invoker) (kurento.Response, error) {
+		withCtx = append(withCtx, ctx.Value(ctxKey{}) != nil)
+		return next(ctx, req)
+	})
+
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, true))
+	built, err := topology.Build(ctx, conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the built objects outlive ctx
+	cancel()
+	if err := built.Elements["a"].Release(); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(withCtx, []bool{true, true, true, false}) {
+		t.Errorf("requests made with ctx: %v", withCtx)
+	}
+}
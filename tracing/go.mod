@@ -0,0 +1,16 @@
+module github.com/safermobility/kurento-go/v6/tracing
+
+go 1.24
+
+require (
+	github.com/safermobility/kurento-go/v6 v6.0.0-20261018133638-5dadf9de3334
+	go.opentelemetry.io/otel v1.32.0
+	go.opentelemetry.io/otel/trace v1.32.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	go.opentelemetry.io/otel/metric v1.32.0 // indirect
+	golang.org/x/net v0.37.0 // indirect
+)
@@ -0,0 +1,143 @@
+// Package tracing traces the JSON-RPC requests and the events of a Kurento
+// connection with OpenTelemetry.
+//
+// Every request is a client span, child of the span of the context of the
+// connection it is made with:
+//
+//	conn.SetTracer(tracing.NewTracer(nil))
+//	answer, err := kurento.WithContext(ctx, webrtc).ProcessOffer(offer)
+//
+// Events are delivered in spans of their own, linked to the span which
+// created the object that raised them.
+package tracing
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/safermobility/kurento-go/v6/tracing"
+
+// Attributes of the spans.
+const (
+	ObjectIdKey     = attribute.Key("kurento.object.id")
+	OperationKey    = attribute.Key("kurento.operation")
+	SessionIdKey    = attribute.Key("kurento.session.id")
+	EventKey        = attribute.Key("kurento.event")
+	RequestIdKey    = attribute.Key("rpc.jsonrpc.request_id")
+	ErrorCodeKey    = attribute.Key("rpc.jsonrpc.error_code")
+	ErrorMessageKey = attribute.Key("rpc.jsonrpc.error_message")
+)
+
+// Tracer is a kurento.Tracer creating OpenTelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+
+	lock sync.Mutex
+	// span which created each object, for the links of the events
+	objects map[string]trace.SpanContext
+}
+
+// NewTracer returns a tracer using the spans of provider, or of the global
+// provider if nil.
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{
+		tracer:  provider.Tracer(instrumentationName),
+		objects: make(map[string]trace.SpanContext),
+	}
+}
+
+// StartRequest implements kurento.Tracer. The span is named after the
+// method and operation, e.g. "kurento.invoke processOffer" or
+// "kurento.create WebRtcEndpoint".
+func (t *Tracer) StartRequest(ctx context.Context, req map[string]interface{}) func(kurento.Response) {
+	method, _ := req["method"].(string)
+	params, _ := req["params"].(map[string]interface{})
+	object, _ := params["object"].(string)
+	operation, _ := params["operation"].(string)
+	if operation == "" {
+		operation, _ = params["type"].(string)
+	}
+	sessionId, _ := params["sessionId"].(string)
+	if sessionId == "" {
+		sessionId, _ = req["sessionId"].(string)
+	}
+
+	name := "kurento." + method
+	attrs := []attribute.KeyValue{
+		attribute.String("rpc.system", "jsonrpc"),
+		attribute.String("rpc.method", method),
+	}
+	if operation != "" {
+		name += " " + operation
+		attrs = append(attrs, OperationKey.String(operation))
+	}
+	if object != "" {
+		attrs = append(attrs, ObjectIdKey.String(object))
+	}
+	if sessionId != "" {
+		attrs = append(attrs, SessionIdKey.String(sessionId))
+	}
+	if id, ok := req["id"].(int64); ok {
+		attrs = append(attrs, RequestIdKey.String(strconv.FormatInt(id, 10)))
+	}
+
+	_, span := t.tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	return func(r kurento.Response) {
+		switch {
+		case r.Error != nil:
+			span.SetAttributes(ErrorCodeKey.Int64(r.Error.Code), ErrorMessageKey.String(r.Error.Message))
+			span.SetStatus(codes.Error, r.Error.Message)
+		case method == "create":
+			if id, ok := r.Result["value"].(string); ok {
+				span.SetAttributes(ObjectIdKey.String(id))
+				t.lock.Lock()
+				t.objects[id] = span.SpanContext()
+				t.lock.Unlock()
+			}
+		case method == "release":
+			t.forget(object)
+		}
+		span.End()
+	}
+}
+
+// forget removes the released object, and the objects it contains.
+func (t *Tracer) forget(object string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for id := range t.objects {
+		if id == object || strings.HasPrefix(id, object+"/") {
+			delete(t.objects, id)
+		}
+	}
+}
+
+// StartEvent implements kurento.Tracer. The span is linked to the span of
+// the creation of object, if it was created on this connection.
+func (t *Tracer) StartEvent(event, object string, data map[string]interface{}) func() {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(EventKey.String(event), ObjectIdKey.String(object)),
+	}
+	t.lock.Lock()
+	if creation, ok := t.objects[object]; ok {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: creation}))
+	}
+	t.lock.Unlock()
+
+	_, span := t.tracer.Start(context.Background(), "kurento.event "+event, opts...)
+	return func() { span.End() }
+}
@@ -0,0 +1,184 @@
+package tracing
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// span is a span recorded by a provider.
+type span struct {
+	noop.Span
+
+	name    string
+	config  trace.SpanConfig
+	context trace.SpanContext
+	attrs   map[attribute.Key]attribute.Value
+	status  codes.Code
+	ended   bool
+}
+
+func (s *span) SpanContext() trace.SpanContext { return s.context }
+
+func (s *span) SetAttributes(kv ...attribute.KeyValue) {
+	for _, a := range kv {
+		s.attrs[a.Key] = a.Value
+	}
+}
+
+func (s *span) SetStatus(code codes.Code, _ string) { s.status = code }
+
+func (s *span) End(...trace.SpanEndOption) { s.ended = true }
+
+// provider gives the recorder as tracer.
+type provider struct {
+	embedded.TracerProvider
+
+	recorder
+}
+
+func (p *provider) Tracer(string, ...trace.TracerOption) trace.Tracer { return &p.recorder }
+
+// recorder records the spans it starts.
+type recorder struct {
+	embedded.Tracer
+
+	lock  sync.Mutex
+	spans []*span
+}
+
+func (p *recorder) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	s := &span{
+		name:   name,
+		config: trace.NewSpanStartConfig(opts...),
+		context: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID: trace.TraceID{1},
+			SpanID:  trace.SpanID{byte(len(p.spans) + 1)},
+		}),
+		attrs: make(map[attribute.Key]attribute.Value),
+	}
+	for _, a := range s.config.Attributes() {
+		s.attrs[a.Key] = a.Value
+	}
+	p.spans = append(p.spans, s)
+	return trace.ContextWithSpan(ctx, s), s
+}
+
+func (p *recorder) last() *span {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	return p.spans[len(p.spans)-1]
+}
+
+func TestRequests(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      map[string]interface{}
+		response kurento.Response
+		want     string
+		attrs    map[attribute.Key]string
+		failed   bool
+	}{
+		{
+			name: "create",
+			req: map[string]interface{}{
+				"id":     int64(3),
+				"method": "create",
+				"params": map[string]interface{}{"type": "WebRtcEndpoint", "sessionId": "s1"},
+			},
+			response: kurento.Response{Result: map[string]interface{}{"value": "p/w"}},
+			want:     "kurento.create WebRtcEndpoint",
+			attrs:    map[attribute.Key]string{RequestIdKey: "3", ObjectIdKey: "p/w", SessionIdKey: "s1", OperationKey: "WebRtcEndpoint"},
+		},
+		{
+			name: "invoke",
+			req: map[string]interface{}{
+				"method":    "invoke",
+				"sessionId": "s1",
+				"params":    map[string]interface{}{"object": "p/w", "operation": "processOffer"},
+			},
+			response: kurento.Response{Error: &kurento.Error{Code: 40101, Message: "failed"}},
+			want:     "kurento.invoke processOffer",
+			attrs:    map[attribute.Key]string{ObjectIdKey: "p/w", SessionIdKey: "s1", ErrorMessageKey: "failed"},
+			failed:   true,
+		},
+		{
+			name:  "ping",
+			req:   map[string]interface{}{"method": "ping"},
+			want:  "kurento.ping",
+			attrs: map[attribute.Key]string{"rpc.method": "ping"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &provider{}
+			end := NewTracer(p).StartRequest(context.Background(), tt.req)
+			end(tt.response)
+
+			s := p.last()
+			if s.name != tt.want || !s.ended || s.config.SpanKind() != trace.SpanKindClient {
+				t.Errorf("span %q, ended %v, kind %s", s.name, s.ended, s.config.SpanKind())
+			}
+			for k, v := range tt.attrs {
+				if got := s.attrs[k].Emit(); got != v {
+					t.Errorf("%s = %q, want %q", k, got, v)
+				}
+			}
+			if (s.status == codes.Error) != tt.failed {
+				t.Errorf("status %s", s.status)
+			}
+		})
+	}
+}
+
+func TestEventLinks(t *testing.T) {
+	p := &provider{}
+	tracer := NewTracer(p)
+	request := func(method string, params map[string]interface{}, value string) trace.SpanContext {
+		tracer.StartRequest(context.Background(), map[string]interface{}{"method": method, "params": params})(
+			kurento.Response{Result: map[string]interface{}{"value": value}})
+		return p.last().context
+	}
+	pipeline := request("create", map[string]interface{}{"type": "MediaPipeline"}, "p")
+	player := request("create", map[string]interface{}{"type": "PlayerEndpoint"}, "p/pl")
+
+	tests := []struct {
+		name   string
+		before func()
+		object string
+		want   []trace.SpanContext
+	}{
+		{"pipeline", nil, "p", []trace.SpanContext{pipeline}},
+		{"player", nil, "p/pl", []trace.SpanContext{player}},
+		{"not created here", nil, "q", nil},
+		{"released with the pipeline", func() { request("release", map[string]interface{}{"object": "p"}, "") }, "p/pl", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.before != nil {
+				tt.before()
+			}
+			tracer.StartEvent("EndOfStream", tt.object, nil)()
+
+			s := p.last()
+			links := s.config.Links()
+			if s.name != "kurento.event EndOfStream" || !s.ended || s.attrs[ObjectIdKey].Emit() != tt.object {
+				t.Errorf("span %q of %s", s.name, s.attrs[ObjectIdKey].Emit())
+			}
+			if len(links) != len(tt.want) || len(links) > 0 && !links[0].SpanContext.Equal(tt.want[0]) {
+				t.Errorf("links = %v, want %v", links, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package kurento
+
+import (
+	"errors"
+	"sync"
+)
+
+// TrickleCallbacks are the signaling functions used by a TrickleSession to
+// reach the remote peer.
+//
+// They are called from the event loop of the Connection, so they must not
+// wait for an answer of the Kurento server.
+type TrickleCallbacks struct {
+	// SendCandidate sends a local candidate to the remote peer. Required.
+	SendCandidate func(candidate IceCandidate) error
+
+	// SendEndOfCandidates tells the remote peer that all the local candidates
+	// were sent. Optional.
+	SendEndOfCandidates func() error
+
+	// OnError is called when a local candidate or the end of candidates can
+	// not be sent. Optional.
+	OnError func(err error)
+}
+
+// TrickleSession runs the SDP negotiation and trickle ICE exchange of a
+// WebRtcEndpoint with a remote peer.
+//
+// Local candidates found before the SDP exchange completes are buffered and
+// sent once the remote peer has received the SDP. Remote candidates received
+// before the remote description is processed by KMS are buffered and added
+// once it is. Candidate gathering is started when the exchange completes.
+type TrickleSession struct {
+	endpoint  *WebRtcEndpoint
+	callbacks TrickleCallbacks
+
+	// local side, written from the event loop
+	localLock     sync.Mutex
+	localReady    bool
+	localPending  []IceCandidate
+	gatheringDone bool
+	endSent       bool
+
+	// remote side, written by the caller
+	remoteLock    sync.Mutex
+	remoteReady   bool
+	remotePending []IceCandidate
+
+	subscriptions map[string]string // event -> handlerId
+	closed        bool
+}
+
+var errTrickleClosed = errors.New("kurento: trickle session is closed")
+
+// NewTrickleSession subscribes to the ICE events of endpoint. Subscriptions
+// are made before any negotiation, so no candidate can be missed.
+func NewTrickleSession(endpoint *WebRtcEndpoint, callbacks TrickleCallbacks) (*TrickleSession, error) {
+	if callbacks.SendCandidate == nil {
+		return nil, errors.New("kurento: TrickleCallbacks.SendCandidate is required")
+	}
+	s := &TrickleSession{
+		endpoint:      endpoint,
+		callbacks:     callbacks,
+		subscriptions: make(map[string]string),
+	}
+
+	handlerId, err := endpoint.SubscribeIceCandidateFound(func(ev IceCandidateFoundEvent) {
+		s.localCandidate(ev.Candidate)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.subscriptions["IceCandidateFound"] = handlerId
+
+	handlerId, err = endpoint.SubscribeIceGatheringDone(func(IceGatheringDoneEvent) {
+		s.localGatheringDone()
+	})
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.subscriptions["IceGatheringDone"] = handlerId
+
+	return s, nil
+}
+
+// AcceptOffer processes an offer of the remote peer and gives the answer to
+// sendAnswer. Local candidates are sent only after sendAnswer returns, then
+// gathering starts.
+func (s *TrickleSession) AcceptOffer(offer string, sendAnswer func(answer string) error) error {
+	if s.closed {
+		return errTrickleClosed
+	}
+	answer, err := s.endpoint.ProcessOffer(offer)
+	if err != nil {
+		return err
+	}
+	if err := s.remoteDescriptionSet(); err != nil {
+		return err
+	}
+	if err := sendAnswer(answer); err != nil {
+		return err
+	}
+	s.localDescriptionSent()
+	return s.endpoint.GatherCandidates()
+}
+
+// CreateOffer generates an offer and gives it to sendOffer. Local candidates
+// are sent only after sendOffer returns, then gathering starts. The answer of
+// the remote peer must be given to ProcessAnswer. The media left false in
+// options are not offered.
+func (s *TrickleSession) CreateOffer(options OfferOptions, sendOffer func(offer string) error) error {
+	if s.closed {
+		return errTrickleClosed
+	}
+	offer, err := s.endpoint.GenerateOffer(options)
+	if err != nil {
+		return err
+	}
+	if err := sendOffer(offer); err != nil {
+		return err
+	}
+	s.localDescriptionSent()
+	return s.endpoint.GatherCandidates()
+}
+
+// ProcessAnswer processes the answer of the remote peer to an offer made by
+// CreateOffer, then adds the remote candidates received meanwhile.
+func (s *TrickleSession) ProcessAnswer(answer string) error {
+	if s.closed {
+		return errTrickleClosed
+	}
+	if _, err := s.endpoint.ProcessAnswer(answer); err != nil {
+		return err
+	}
+	return s.remoteDescriptionSet()
+}
+
+// AddRemoteCandidate gives a candidate of the remote peer to the endpoint,
+// or keeps it until the remote description is processed. An empty candidate,
+// which browsers use to signal the end of candidates, is ignored.
+func (s *TrickleSession) AddRemoteCandidate(candidate IceCandidate) error {
+	if s.closed {
+		return errTrickleClosed
+	}
+	if candidate.Candidate == "" {
+		return nil
+	}
+
+	s.remoteLock.Lock()
+	defer s.remoteLock.Unlock()
+
+	if !s.remoteReady {
+		s.remotePending = append(s.remotePending, candidate)
+		return nil
+	}
+	return s.endpoint.AddIceCandidate(candidate)
+}
+
+// Close removes the event subscriptions of the session. The endpoint is not
+// released.
+func (s *TrickleSession) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	var ret error
+	for event, handlerId := range s.subscriptions {
+		if err := s.endpoint.Unsubscribe(event, handlerId); err != nil && ret == nil {
+			ret = err
+		}
+	}
+	return ret
+}
+
+// remoteDescriptionSet adds the remote candidates kept so far, in order.
+func (s *TrickleSession) remoteDescriptionSet() error {
+	s.remoteLock.Lock()
+	defer s.remoteLock.Unlock()
+
+	s.remoteReady = true
+	pending := s.remotePending
+	s.remotePending = nil
+	for i, candidate := range pending {
+		if err := s.endpoint.AddIceCandidate(candidate); err != nil {
+			s.remotePending = pending[i+1:]
+			return err
+		}
+	}
+	return nil
+}
+
+// localDescriptionSent sends the local candidates kept so far, in order.
+func (s *TrickleSession) localDescriptionSent() {
+	s.localLock.Lock()
+	defer s.localLock.Unlock()
+
+	s.localReady = true
+	for _, candidate := range s.localPending {
+		s.send(candidate)
+	}
+	s.localPending = nil
+	if s.gatheringDone {
+		s.sendEnd()
+	}
+}
+
+func (s *TrickleSession) localCandidate(candidate IceCandidate) {
+	s.localLock.Lock()
+	defer s.localLock.Unlock()
+
+	if !s.localReady {
+		s.localPending = append(s.localPending, candidate)
+		return
+	}
+	s.send(candidate)
+}
+
+func (s *TrickleSession) localGatheringDone() {
+	s.localLock.Lock()
+	defer s.localLock.Unlock()
+
+	s.gatheringDone = true
+	if s.localReady {
+		s.sendEnd()
+	}
+}
+
+// send must be called with localLock held.
+func (s *TrickleSession) send(candidate IceCandidate) {
+	if err := s.callbacks.SendCandidate(candidate); err != nil {
+		s.error(err)
+	}
+}
+
+// sendEnd must be called with localLock held.
+func (s *TrickleSession) sendEnd() {
+	if s.endSent || s.callbacks.SendEndOfCandidates == nil {
+		return
+	}
+	s.endSent = true
+	if err := s.callbacks.SendEndOfCandidates(); err != nil {
+		s.error(err)
+	}
+}
+
+func (s *TrickleSession) error(err error) {
+	if s.callbacks.OnError != nil {
+		s.callbacks.OnError(err)
+	}
+}
@@ -0,0 +1,151 @@
+package kurento_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+// candidateFound sends a local candidate of the endpoint "pipe/webrtc".
+func (s *script) candidateFound(candidate string) {
+	s.event("pipe/webrtc", "IceCandidateFound", map[string]interface{}{
+		"candidate": map[string]interface{}{"candidate": candidate, "sdpMid": "0", "sdpMLineIndex": 0},
+	})
+}
+
+func (s *script) addIceCandidate(candidate string) {
+	s.call("invoke", map[string]interface{}{
+		"object":          "pipe/webrtc",
+		"operation":       "addIceCandidate",
+		"operationParams": map[string]interface{}{"candidate": map[string]interface{}{"candidate": candidate}},
+	}, nil)
+}
+
+func TestTrickleSession(t *testing.T) {
+	tests := []struct {
+		name   string
+		script func(s *script)
+		// negotiate runs the negotiation, with a remote candidate received
+		// before and after it
+		negotiate func(ts *kurento.TrickleSession, signal func(string)) error
+		want      []string
+	}{
+		{
+			name: "answer",
+			script: func(s *script) {
+				// found before the answer is sent
+				s.candidateFound("local1")
+				s.invoke("pipe/webrtc", "processOffer", "answer")
+				s.addIceCandidate("remote1")
+				s.invoke("pipe/webrtc", "gatherCandidates", true)
+				s.candidateFound("local2")
+				s.event("pipe/webrtc", "IceGatheringDone", nil)
+				s.addIceCandidate("remote2")
+			},
+			negotiate: func(ts *kurento.TrickleSession, signal func(string)) error {
+				return ts.AcceptOffer("offer", func(answer string) error {
+					signal("send " + answer)
+					return nil
+				})
+			},
+			want: []string{"send answer", "candidate local1", "candidate local2", "end"},
+		},
+		{
+			name: "offer",
+			script: func(s *script) {
+				s.invoke("pipe/webrtc", "generateOffer", "offer")
+				s.invoke("pipe/webrtc", "gatherCandidates", true)
+				s.candidateFound("local1")
+				s.event("pipe/webrtc", "IceGatheringDone", nil)
+				s.invoke("pipe/webrtc", "processAnswer", "")
+				s.addIceCandidate("remote1")
+				s.addIceCandidate("remote2")
+			},
+			negotiate: func(ts *kurento.TrickleSession, signal func(string)) error {
+				err := ts.CreateOffer(kurento.OfferOptions{OfferToReceiveAudio: true}, func(offer string) error {
+					signal("send " + offer)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return ts.ProcessAnswer("answer")
+			},
+			want: []string{"send offer", "candidate local1", "end"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &script{}
+			s.subscribe("pipe/webrtc", "IceCandidateFound", "found")
+			s.subscribe("pipe/webrtc", "IceGatheringDone", "done")
+			tt.script(s)
+			s.unsubscribe("pipe/webrtc")
+			s.unsubscribe("pipe/webrtc")
+			conn, srv := s.serve(t)
+
+			endpoint := &kurento.WebRtcEndpoint{}
+			kurento.HydrateMediaObject("pipe/webrtc", nil, conn, endpoint)
+
+			var lock sync.Mutex
+			var got []string
+			ended := make(chan struct{})
+			signal := func(message string) {
+				lock.Lock()
+				defer lock.Unlock()
+				got = append(got, message)
+			}
+			ts, err := kurento.NewTrickleSession(endpoint, kurento.TrickleCallbacks{
+				SendCandidate: func(c kurento.IceCandidate) error {
+					signal("candidate " + c.Candidate)
+					return nil
+				},
+				SendEndOfCandidates: func() error {
+					signal("end")
+					close(ended)
+					return nil
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// kept until the remote description is processed
+			if err := ts.AddRemoteCandidate(kurento.IceCandidate{Candidate: "remote1"}); err != nil {
+				t.Fatal(err)
+			}
+			if err := tt.negotiate(ts, signal); err != nil {
+				t.Fatal(err)
+			}
+			wait(t, ended)
+			if err := ts.AddRemoteCandidate(kurento.IceCandidate{Candidate: "remote2"}); err != nil {
+				t.Fatal(err)
+			}
+			// the end of candidates of browsers
+			if err := ts.AddRemoteCandidate(kurento.IceCandidate{}); err != nil {
+				t.Fatal(err)
+			}
+			if err := ts.Close(); err != nil {
+				t.Fatal(err)
+			}
+			done(t, srv)
+
+			if err := ts.AddRemoteCandidate(kurento.IceCandidate{Candidate: "remote3"}); err == nil {
+				t.Error("candidate added after Close")
+			}
+			lock.Lock()
+			defer lock.Unlock()
+			if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+				t.Errorf("signaled %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrickleSessionCallbacks(t *testing.T) {
+	if _, err := kurento.NewTrickleSession(&kurento.WebRtcEndpoint{}, kurento.TrickleCallbacks{}); err == nil {
+		t.Error("session without SendCandidate")
+	}
+}
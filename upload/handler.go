@@ -0,0 +1,234 @@
+// Package upload receives the recordings a RecorderEndpoint sends to an
+// HTTP(S) URI, as a chunked POST request.
+//
+// Each recording gets a URI holding a random token, which identifies the
+// upload when it is received. Tokens which are not used within the TTL of
+// the handler expire:
+//
+//	h := upload.NewHandler(upload.FileSink{Dir: "/var/recordings", Ext: ".webm"})
+//	http.Handle("/recordings/", h)
+//	u, _ := h.NewUpload("http://10.0.0.1:8080/recordings/", ".webm")
+//	pipeline.Create(recorder, kurento.RecorderEndpointOptions{Uri: u.Uri}.ConstructorParams())
+//	...
+//	result, err := h.Wait(ctx, u.Token)
+package upload
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrUnknownToken = errors.New("upload: unknown token")
+	ErrDuplicate    = errors.New("upload: token already used")
+	ErrExpired      = errors.New("upload: token expired")
+)
+
+// DefaultTTL is the TTL of the handlers returned by NewHandler.
+const DefaultTTL = 24 * time.Hour
+
+// Upload is an expected upload.
+type Upload struct {
+	Token string
+
+	// Uri is the destination to give to the recorder.
+	Uri string
+}
+
+// Result is the outcome of an upload.
+type Result struct {
+	Token string
+
+	// Bytes is the number of bytes stored.
+	Bytes int64
+
+	Start time.Time
+	End   time.Time
+
+	// Err is the error which interrupted the upload, if any.
+	Err error
+}
+
+type pendingUpload struct {
+	started bool
+	done    chan struct{}
+	result  Result
+	// expiry forgets the upload once its TTL is over.
+	expiry *time.Timer
+}
+
+// Handler is an http.Handler receiving uploads into a Sink. Only requests
+// whose last path element is the token of an upload created with NewUpload,
+// optionally followed by an extension, are accepted.
+type Handler struct {
+	Sink Sink
+
+	// OnComplete is called at the end of every upload. Optional.
+	OnComplete func(Result)
+
+	// TTL is the time after which an upload which has not started is
+	// forgotten, and its waiters get ErrExpired. The result of an upload is
+	// forgotten TTL after its end. 0 keeps the uploads until Forget.
+	TTL time.Duration
+
+	lock    sync.Mutex
+	uploads map[string]*pendingUpload
+}
+
+// NewHandler returns a handler storing uploads in sink.
+func NewHandler(sink Sink) *Handler {
+	return &Handler{
+		Sink:    sink,
+		TTL:     DefaultTTL,
+		uploads: make(map[string]*pendingUpload),
+	}
+}
+
+// expire forgets u after the TTL, unless it is running then. It must be
+// called with the lock held.
+func (h *Handler) expire(token string, u *pendingUpload) {
+	if h.TTL <= 0 {
+		return
+	}
+	var expiry *time.Timer
+	expiry = time.AfterFunc(h.TTL, func() {
+		h.lock.Lock()
+		defer h.lock.Unlock()
+
+		if h.uploads[token] != u || u.expiry != expiry || (u.started && u.result.End.IsZero()) {
+			return
+		}
+		delete(h.uploads, token)
+		if !u.started {
+			u.result = Result{Token: token, Err: ErrExpired}
+			close(u.done)
+		}
+	})
+	u.expiry = expiry
+}
+
+// NewUpload registers an upload and returns its URI, baseURL followed by a
+// new token and ext. baseURL is the URL the handler is served at, reachable
+// from the media server.
+func (h *Handler) NewUpload(baseURL, ext string) (Upload, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return Upload{}, err
+	}
+	token := hex.EncodeToString(raw)
+
+	u := &pendingUpload{done: make(chan struct{})}
+	h.lock.Lock()
+	h.uploads[token] = u
+	h.expire(token, u)
+	h.lock.Unlock()
+
+	if !strings.HasSuffix(baseURL, "/") {
+		baseURL += "/"
+	}
+	return Upload{Token: token, Uri: baseURL + token + ext}, nil
+}
+
+// Forget unregisters an upload. Its result is no longer available.
+func (h *Handler) Forget(token string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if u, ok := h.uploads[token]; ok && u.expiry != nil {
+		u.expiry.Stop()
+	}
+	delete(h.uploads, token)
+}
+
+// Wait waits for the end of the upload of token and returns its result. It
+// fails with ErrExpired if the upload does not start within the TTL.
+func (h *Handler) Wait(ctx context.Context, token string) (Result, error) {
+	h.lock.Lock()
+	u, ok := h.uploads[token]
+	h.lock.Unlock()
+	if !ok {
+		return Result{}, ErrUnknownToken
+	}
+
+	select {
+	case <-u.done:
+		return u.result, u.result.Err
+	case <-ctx.Done():
+		return Result{}, ctx.Err()
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodPut {
+		w.Header().Set("Allow", "POST, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := path.Base(r.URL.Path)
+	if i := strings.IndexByte(token, '.'); i >= 0 {
+		token = token[:i]
+	}
+
+	h.lock.Lock()
+	u, ok := h.uploads[token]
+	started := ok && u.started
+	if ok {
+		u.started = true
+	}
+	h.lock.Unlock()
+	switch {
+	case !ok:
+		http.Error(w, ErrUnknownToken.Error(), http.StatusNotFound)
+		return
+	case started:
+		http.Error(w, ErrDuplicate.Error(), http.StatusConflict)
+		return
+	}
+
+	result := h.receive(r, token)
+	h.lock.Lock()
+	u.result = result
+	close(u.done)
+	if u.expiry != nil {
+		u.expiry.Stop()
+	}
+	h.expire(token, u)
+	h.lock.Unlock()
+	if h.OnComplete != nil {
+		h.OnComplete(result)
+	}
+
+	if result.Err != nil {
+		http.Error(w, result.Err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) receive(r *http.Request, token string) Result {
+	result := Result{Token: token, Start: time.Now()}
+	dst, err := h.Sink.Create(r.Context(), token, r.Header.Get("Content-Type"))
+	if err != nil {
+		result.Err = err
+		result.End = time.Now()
+		return result
+	}
+
+	result.Bytes, err = io.Copy(dst, r.Body)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	result.Err = err
+	result.End = time.Now()
+	return result
+}
@@ -0,0 +1,139 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memorySink stores the uploads in memory.
+type memorySink struct {
+	lock    sync.Mutex
+	uploads map[string]*bytes.Buffer
+}
+
+func (s *memorySink) writer(token string) (io.Writer, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	b := &bytes.Buffer{}
+	s.uploads[token] = b
+	return b, nil
+}
+
+func TestHandler(t *testing.T) {
+	const ttl = 100 * time.Millisecond
+	tests := []struct {
+		name string
+		// delay before the upload, none if negative
+		delay time.Duration
+		// path of the upload, the URI of the token if empty
+		path       string
+		status     int
+		waitErr    error
+		afterDelay time.Duration
+		afterErr   error
+	}{
+		{name: "upload", status: http.StatusOK},
+		{name: "unknown token", path: "/recordings/0123.webm", status: http.StatusNotFound, waitErr: ErrExpired},
+		{name: "not started", delay: -1, waitErr: ErrExpired},
+		{name: "expired", delay: 2 * ttl, status: http.StatusNotFound, waitErr: ErrExpired},
+		{name: "result kept", status: http.StatusOK, afterDelay: ttl / 4},
+		{name: "result expired", status: http.StatusOK, afterDelay: 2 * ttl, afterErr: ErrUnknownToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := &memorySink{uploads: make(map[string]*bytes.Buffer)}
+			h := NewHandler(WriterSink(sink.writer))
+			h.TTL = ttl
+			ts := httptest.NewServer(h)
+			defer ts.Close()
+
+			u, err := h.NewUpload(ts.URL+"/recordings", ".webm")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(u.Uri, ts.URL+"/recordings/"+u.Token) {
+				t.Fatalf("uri = %s", u.Uri)
+			}
+
+			waited := make(chan error, 1)
+			go func() {
+				_, err := h.Wait(context.Background(), u.Token)
+				waited <- err
+			}()
+
+			if tt.delay >= 0 {
+				time.Sleep(tt.delay)
+				uri := u.Uri
+				if tt.path != "" {
+					uri = ts.URL + tt.path
+				}
+				resp, err := http.Post(uri, "video/webm", strings.NewReader("media"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				resp.Body.Close()
+				if resp.StatusCode != tt.status {
+					t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+				}
+			}
+
+			select {
+			case err := <-waited:
+				if !errors.Is(err, tt.waitErr) {
+					t.Errorf("Wait() = %v, want %v", err, tt.waitErr)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("Wait() did not return")
+			}
+			if tt.status == http.StatusOK && sink.uploads[u.Token].String() != "media" {
+				t.Errorf("stored %q", sink.uploads[u.Token])
+			}
+
+			if tt.afterDelay > 0 {
+				time.Sleep(tt.afterDelay)
+				result, err := h.Wait(context.Background(), u.Token)
+				if !errors.Is(err, tt.afterErr) {
+					t.Errorf("Wait() after %s = %v, want %v", tt.afterDelay, err, tt.afterErr)
+				}
+				if err == nil && result.Bytes != 5 {
+					t.Errorf("result = %+v", result)
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerDuplicate(t *testing.T) {
+	h := NewHandler(WriterSink(func(string) (io.Writer, error) { return io.Discard, nil }))
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+
+	u, err := h.NewUpload(ts.URL, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []int{http.StatusOK, http.StatusConflict} {
+		resp, err := http.Post(u.Uri, "video/webm", strings.NewReader("media"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("status = %d, want %d", resp.StatusCode, want)
+		}
+	}
+
+	h.Forget(u.Token)
+	if _, err := h.Wait(context.Background(), u.Token); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("Wait() after Forget = %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Sink stores the body of uploads.
+type Sink interface {
+	// Create opens the destination of the upload of token. The body is
+	// written to it, then it is closed; an error from Close fails the
+	// upload.
+	Create(ctx context.Context, token, contentType string) (io.WriteCloser, error)
+}
+
+// FileSink stores every upload in a file of Dir named after its token.
+type FileSink struct {
+	Dir string
+
+	// Ext is appended to the token, e.g. ".webm".
+	Ext string
+}
+
+// Create implements Sink.
+func (s FileSink) Create(ctx context.Context, token, contentType string) (io.WriteCloser, error) {
+	return os.Create(filepath.Join(s.Dir, token+s.Ext))
+}
+
+// WriterSink stores uploads in the writer returned by the function for their
+// token. The writer is closed at the end of the upload if it is an
+// io.WriteCloser.
+type WriterSink func(token string) (io.Writer, error)
+
+// Create implements Sink.
+func (s WriterSink) Create(ctx context.Context, token, contentType string) (io.WriteCloser, error) {
+	w, err := s(token)
+	if err != nil {
+		return nil, err
+	}
+	if wc, ok := w.(io.WriteCloser); ok {
+		return wc, nil
+	}
+	return nopCloser{w}, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// ObjectStore is the part of an object storage client needed by StoreSink,
+// e.g. a thin wrapper around an S3 or GCS client. PutObject must read body
+// until EOF or fail.
+type ObjectStore interface {
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) error
+}
+
+// StoreSink streams uploads to an object store, under the key Prefix+token+Ext.
+type StoreSink struct {
+	Store  ObjectStore
+	Prefix string
+	Ext    string
+}
+
+// Create implements Sink. The object is written while the upload is
+// received, and Close returns once PutObject has returned.
+func (s StoreSink) Create(ctx context.Context, token, contentType string) (io.WriteCloser, error) {
+	pr, pw := io.Pipe()
+	w := &storeWriter{PipeWriter: pw, done: make(chan error, 1)}
+	go func() {
+		err := s.Store.PutObject(ctx, s.Prefix+token+s.Ext, pr, contentType)
+		// unblock the writer if the store stopped reading early
+		pr.CloseWithError(err)
+		w.done <- err
+	}()
+	return w, nil
+}
+
+type storeWriter struct {
+	*io.PipeWriter
+	done chan error
+}
+
+func (w *storeWriter) Close() error {
+	w.PipeWriter.Close()
+	return <-w.done
+}
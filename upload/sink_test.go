@@ -0,0 +1,108 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// memoryStore is an ObjectStore keeping the objects in memory. It fails
+// once it read failAfter bytes, if positive.
+type memoryStore struct {
+	failAfter int64
+
+	lock    sync.Mutex
+	objects map[string]string
+	types   map[string]string
+}
+
+var errStore = errors.New("store failed")
+
+func (s *memoryStore) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	if s.failAfter > 0 {
+		io.CopyN(io.Discard, body, s.failAfter)
+		return errStore
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.objects[key] = string(data)
+	s.types[key] = contentType
+	return nil
+}
+
+func TestSinks(t *testing.T) {
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	store := &memoryStore{objects: make(map[string]string), types: make(map[string]string)}
+
+	tests := []struct {
+		name    string
+		sink    Sink
+		stored  func() string
+		wantErr error
+	}{
+		{
+			name: "file",
+			sink: FileSink{Dir: dir, Ext: ".webm"},
+			stored: func() string {
+				data, _ := os.ReadFile(filepath.Join(dir, "t1.webm"))
+				return string(data)
+			},
+		},
+		{
+			name:   "writer",
+			sink:   WriterSink(func(string) (io.Writer, error) { return &buf, nil }),
+			stored: buf.String,
+		},
+		{
+			name: "store",
+			sink: StoreSink{Store: store, Prefix: "uploads/", Ext: ".webm"},
+			stored: func() string {
+				if store.types["uploads/t1.webm"] != "video/webm" {
+					return ""
+				}
+				return store.objects["uploads/t1.webm"]
+			},
+		},
+		{
+			name:    "store failing",
+			sink:    StoreSink{Store: &memoryStore{failAfter: 4}},
+			wantErr: errStore,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := tt.sink.Create(context.Background(), "t1", "video/webm")
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, werr := io.WriteString(w, "media data")
+			err = errors.Join(werr, w.Close())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil {
+				if got := tt.stored(); got != "media data" {
+					t.Errorf("stored %q", got)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterSinkError(t *testing.T) {
+	failed := errors.New("no writer")
+	sink := WriterSink(func(string) (io.Writer, error) { return nil, failed })
+	if _, err := sink.Create(context.Background(), "t1", ""); !errors.Is(err, failed) {
+		t.Errorf("Create() = %v", err)
+	}
+}
@@ -1,10 +1,12 @@
 package kurento
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
 	"net"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,7 +21,21 @@ type Error struct {
 	Data    interface{}
 }
 
-const ConnectionLost = -1
+// Error implements the error interface, with the format of the errors
+// returned by the methods of the objects.
+func (e *Error) Error() string {
+	return fmt.Sprintf("[%d] %s %s", e.Code, e.Message, e.Data)
+}
+
+const (
+	ConnectionLost  = -1
+	RequestCanceled = -2
+	// RequestFailed is the code of the errors returned by interceptors
+	RequestFailed = -3
+	// RequestTimeout is the code of the attempts of RetryInterceptor which
+	// were not answered in time
+	RequestTimeout = -4
+)
 
 // Response represents server response
 type Response struct {
@@ -50,8 +66,24 @@ func (e *Event) unmarshal(raw json.RawMessage) bool {
 	return e.Method == "onEvent"
 }
 
+// Connection is a connection to KMS, made by NewConnection. A connection
+// returned by WithContext shares the websocket of its parent, and makes its
+// requests with the context it is bound to. The requests of the zero
+// Connection fail with ConnectionLost.
 type Connection struct {
+	*connState
+	ctx context.Context
+}
+
+// connState is the state shared by a connection and its context-bound
+// copies.
+type connState struct {
+	root      *Connection
 	clientId  *atomic.Int64
+	tracer    atomic.Value // Tracer
+	chain     atomic.Value // []Interceptor
+	tap       atomic.Value // func(bool, []byte)
+	chainLock sync.Mutex
 	eventId   float64
 	clients   threadsafeClientMap
 	host      string
@@ -59,22 +91,157 @@ type Connection struct {
 	SessionId string
 	events    threadsafeSubscriberMap
 	eChan     chan Event
-	Dead      chan bool
-	IsDead    bool
+	// Dead receives a value once the connection is lost.
+	Dead chan bool
+	// Deprecated: IsDead is set without synchronization by the goroutine
+	// reading the websocket; use Lost.
+	IsDead bool
+	dead   atomic.Bool
 }
 
 type threadsafeClientMap struct {
-	clients map[int64]chan Response
+	clients map[int64]*pendingRequest
 	lock    sync.RWMutex
 }
 
+// pendingRequest is a request waiting for its response.
+type pendingRequest struct {
+	client chan Response
+	// done is called with the response, or the error ending the request
+	done func(Response)
+}
+
+// RequestStats describe a request to KMS once it is complete. See
+// MetricsInterceptor.
+type RequestStats struct {
+	// Method is the JSON-RPC method, e.g. "invoke" or "create".
+	Method string
+	// Operation is the invoked operation, or the type of the created object.
+	Operation string
+	// Object is the ID of the target object, if any.
+	Object string
+
+	Duration time.Duration
+	// Error is the error of the response, nil on success.
+	Error *Error
+}
+
+func newRequestStats(req map[string]interface{}) RequestStats {
+	stats := RequestStats{}
+	stats.Method, _ = req["method"].(string)
+	if params, ok := req["params"].(map[string]interface{}); ok {
+		stats.Object, _ = params["object"].(string)
+		if stats.Operation, _ = params["operation"].(string); stats.Operation == "" {
+			stats.Operation, _ = params["type"].(string)
+		}
+	}
+	return stats
+}
+
+// SetWireTap installs fn, which is called with every message sent to KMS,
+// with sent true, or received from it, before the message is handled. fn
+// must not keep message.
+func (c *Connection) SetWireTap(fn func(sent bool, message []byte)) {
+	if c.connState != nil {
+		c.tap.Store(fn)
+	}
+}
+
+func (c *Connection) wireTap(sent bool, message []byte) {
+	if fn, _ := c.tap.Load().(func(bool, []byte)); fn != nil {
+		fn(sent, message)
+	}
+}
+
+// Tracer traces the requests and events of a connection. The tracing
+// package implements it with OpenTelemetry.
+type Tracer interface {
+	// StartRequest is called with the context of the caller before req is
+	// sent. The returned function is called with the response, or the error
+	// ending the request.
+	StartRequest(ctx context.Context, req map[string]interface{}) func(Response)
+
+	// StartEvent is called before the handlers of an event raised by object.
+	// The returned function is called once they returned.
+	StartEvent(event, object string, data map[string]interface{}) func()
+}
+
+// SetTracer installs t, which traces the requests and events of the
+// connection. A nil t disables tracing.
+func (c *Connection) SetTracer(t Tracer) {
+	if c.connState != nil {
+		c.tracer.Store(tracerValue{t})
+	}
+}
+
+// tracerValue wraps a Tracer, as atomic.Value cannot store nil or values
+// of different types.
+type tracerValue struct {
+	Tracer
+}
+
+func (c *Connection) getTracer() Tracer {
+	t, _ := c.tracer.Load().(tracerValue)
+	return t.Tracer
+}
+
+// WithContext returns a connection sharing the websocket of c, whose
+// requests are made with ctx: they are traced as children of the span of
+// ctx, and fail with RequestCanceled once ctx is done. Objects created or
+// returned by its requests are bound to the parent connection.
+func (c *Connection) WithContext(ctx context.Context) *Connection {
+	return &Connection{connState: c.connState, ctx: ctx}
+}
+
+// Context returns the context of the connection, or context.Background().
+func (c *Connection) Context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// base returns the connection c was made from by WithContext, or c.
+func (c *Connection) base() *Connection {
+	if c == nil || c.connState == nil || c.ctx == nil {
+		return c
+	}
+	return c.root
+}
+
+// WithContext returns a copy of obj whose requests are made with ctx, as
+// with Connection.WithContext.
+func WithContext[T IMediaObject](ctx context.Context, obj T) T {
+	c := obj.getConnection()
+	ret := reflect.New(reflect.TypeOf(obj).Elem()).Interface().(T)
+	ret.setId(obj.String())
+	if c != nil {
+		ret.setConnection(c.WithContext(ctx))
+	}
+	return ret
+}
+
+// begin starts the tracing of req, and returns the function ending it.
+func (c *Connection) begin(ctx context.Context, req map[string]interface{}) func(Response) {
+	var end func(Response)
+	if t := c.getTracer(); t != nil {
+		end = t.StartRequest(ctx, req)
+	}
+	return func(r Response) {
+		if end != nil {
+			end(r)
+		}
+	}
+}
+
 type threadsafeSubscriberMap struct {
 	subscribers map[string]map[string]map[string]eventHandler // eventName -> objectId -> handlerId -> handler.
 	lock        sync.RWMutex
 }
 
 func NewConnection(host string) (*Connection, error) {
-	c := new(Connection)
+	c := &Connection{connState: &connState{}}
+	c.root = c
 
 	c.clientId = &atomic.Int64{}
 	c.events = threadsafeSubscriberMap{
@@ -82,7 +249,7 @@ func NewConnection(host string) (*Connection, error) {
 	}
 	c.eChan = make(chan Event, 1)
 	c.clients = threadsafeClientMap{
-		clients: make(map[int64]chan Response),
+		clients: make(map[int64]*pendingRequest),
 	}
 	c.Dead = make(chan bool, 1)
 
@@ -108,7 +275,19 @@ func (c *Connection) Create(m IMediaObject, options map[string]interface{}) erro
 	return elem.Create(m, options)
 }
 
+// ServerManager returns the server manager of KMS, which every connection
+// can use without creating it.
+func (c *Connection) ServerManager() *ServerManager {
+	m := &ServerManager{}
+	m.setConnection(c)
+	m.setId("manager_ServerManager")
+	return m
+}
+
 func (c *Connection) Close() error {
+	if c.connState == nil {
+		return nil
+	}
 	return c.ws.Close()
 }
 
@@ -122,14 +301,14 @@ func (c *Connection) handleResponse() {
 		err := websocket.Message.Receive(c.ws, &message)
 		if err != nil {
 			log.Printf("Error receiving on websocket %s", err)
-			c.IsDead = true
-			c.Dead <- true
+			c.lose()
 			close(c.eChan)
 			break
 		}
 		if logLevel >= LogLevelSilly {
 			log.Println("KURENTO WS: received message")
 		}
+		c.wireTap(false, []byte(message))
 
 		if logLevel > LogLevelDebug {
 			log.Printf("RAW %s", message)
@@ -158,14 +337,19 @@ func (c *Connection) handleResponse() {
 				log.Printf("Response: %v", r)
 			}
 			// if websocket client exists, send response to the channel
-			if c.clients.clients[r.Id] != nil {
+			c.clients.lock.Lock()
+			pending := c.clients.clients[r.Id]
+			delete(c.clients.clients, r.Id)
+			c.clients.lock.Unlock()
+			if pending != nil {
+				pending.done(r)
+				client := pending.client
 				if logLevel >= LogLevelSilly {
 					log.Printf("KURENTO WS: sending response to client: %d\n", r.Id)
 				}
-				c.clients.clients[r.Id] <- r
-				// chanel is read, we can delete it
-				close(c.clients.clients[r.Id])
-				delete(c.clients.clients, r.Id)
+				client <- r
+				// chanel is read, we can close it
+				close(client)
 				if logLevel >= LogLevelSilly {
 					log.Printf("KURENTO WS: finished handling response in client: %d\n", r.Id)
 				}
@@ -200,14 +384,32 @@ func (c *Connection) handleEvents() {
 
 		data := val["data"].(map[string]interface{})
 
-		if handlers, ok := c.events.subscribers[t]; ok {
+		// handlers are copied, so they can subscribe or unsubscribe
+		c.events.lock.RLock()
+		handlers, ok := c.events.subscribers[t]
+		var objHandlers map[string]eventHandler
+		var found bool
+		if ok {
+			objHandlers = make(map[string]eventHandler, len(handlers[objectId]))
+			for name, handler := range handlers[objectId] {
+				objHandlers[name] = handler
+			}
+			_, found = handlers[objectId]
+		}
+		c.events.lock.RUnlock()
+
+		if ok {
 			if logLevel >= LogLevelSilly {
 				log.Printf("KURENTO WS: start event loop 0: %s\n", t)
 			}
-			if objHandlers, ok := handlers[objectId]; ok {
+			if found {
 				if logLevel >= LogLevelSilly {
 					log.Printf("KURENTO WS: start event loop 1: %s - %s\n", t, objectId)
 				}
+				var end func()
+				if tracer := c.getTracer(); tracer != nil {
+					end = tracer.StartEvent(t, objectId, data)
+				}
 				for name, handler := range objHandlers {
 					if logLevel >= LogLevelSilly {
 						log.Printf("KURENTO WS: start event loop 2: %s - %s - %s\n", t, objectId, name)
@@ -217,6 +419,9 @@ func (c *Connection) handleEvents() {
 						log.Printf("KURENTO WS:   end event loop 2: %s - %s - %s\n", t, objectId, name)
 					}
 				}
+				if end != nil {
+					end()
+				}
 				if logLevel >= LogLevelSilly {
 					log.Printf("KURENTO WS:   end event loop 1: %s - %s\n", t, objectId)
 				}
@@ -228,20 +433,76 @@ func (c *Connection) handleEvents() {
 	}
 }
 
+// Request sends req to KMS through the interceptors of the connection, and
+// returns the channel of its response.
 func (c *Connection) Request(req map[string]interface{}) <-chan Response {
-	if c.IsDead {
-		errchan := make(chan Response, 1)
-		errresp := Response{
-			Error: &Error{
-				Code:    ConnectionLost,
-				Message: "No connection to Kurento server",
-			},
+	if c == nil || c.connState == nil {
+		ret := make(chan Response, 1)
+		ret <- connectionLost(req)
+		return ret
+	}
+	interceptors, _ := c.chain.Load().([]Interceptor)
+	if len(interceptors) == 0 {
+		return c.send(c.Context(), req)
+	}
+
+	ret := make(chan Response, 1)
+	go func() {
+		r, err := chain(interceptors, c.invoke)(c.Context(), req)
+		if err != nil && r.Error == nil {
+			r.Error = errorOf(err)
 		}
-		if idInterface, ok := req["id"]; ok {
-			if id, ok := idInterface.(int64); ok {
-				errresp.Id = id
-			}
+		ret <- r
+		close(ret)
+	}()
+	return ret
+}
+
+// sessionId returns the session ID of the connection, or "".
+func (c *Connection) sessionId() string {
+	if c == nil || c.connState == nil {
+		return ""
+	}
+	return c.SessionId
+}
+
+// Lost tells if the connection to KMS is lost. The requests of a lost
+// connection fail with ConnectionLost, and it must be replaced by a new one.
+// A zero Connection is lost.
+func (c *Connection) Lost() bool {
+	return c == nil || c.connState == nil || c.dead.Load()
+}
+
+// lose marks the connection lost, and signals it on Dead the first time.
+func (c *Connection) lose() {
+	if c.dead.CompareAndSwap(false, true) {
+		c.IsDead = true
+		c.Dead <- true
+	}
+}
+
+// connectionLost returns the response of req when there is no connection.
+func connectionLost(req map[string]interface{}) Response {
+	errresp := Response{
+		Error: &Error{
+			Code:    ConnectionLost,
+			Message: "No connection to Kurento server",
+		},
+	}
+	if idInterface, ok := req["id"]; ok {
+		if id, ok := idInterface.(int64); ok {
+			errresp.Id = id
 		}
+	}
+	return errresp
+}
+
+// send sends req to KMS, and returns the channel of its response.
+func (c *Connection) send(ctx context.Context, req map[string]interface{}) <-chan Response {
+	if c.dead.Load() {
+		errchan := make(chan Response, 1)
+		errresp := connectionLost(req)
+		c.begin(ctx, req)(errresp)
 		errchan <- errresp
 		return errchan
 	}
@@ -251,18 +512,27 @@ func (c *Connection) Request(req map[string]interface{}) <-chan Response {
 	if c.SessionId != "" {
 		req["sessionId"] = c.SessionId
 	}
-	c.clients.clients[reqId] = make(chan Response)
+	done := c.begin(ctx, req)
+	client := make(chan Response)
+	c.clients.lock.Lock()
+	c.clients.clients[reqId] = &pendingRequest{client: client, done: done}
+	c.clients.lock.Unlock()
 	if logLevel > 0 {
 		j, _ := json.MarshalIndent(req, "", "    ")
 		log.Println("json", string(j))
 	}
-	err := websocket.JSON.Send(c.ws, req)
+	message, err := json.Marshal(req)
+	if err == nil {
+		c.wireTap(true, message)
+		err = websocket.Message.Send(c.ws, string(message))
+	}
 	if err != nil {
 		log.Printf("Error sending on websocket %s", err)
-		c.Dead <- true
-		c.IsDead = true
+		c.lose()
 
+		c.clients.lock.Lock()
 		delete(c.clients.clients, reqId)
+		c.clients.lock.Unlock()
 
 		errchan := make(chan Response, 1)
 		errresp := Response{
@@ -273,16 +543,74 @@ func (c *Connection) Request(req map[string]interface{}) <-chan Response {
 			},
 		}
 
+		done(errresp)
 		errchan <- errresp
 		return errchan
 	}
-	return c.clients.clients[reqId]
+	if ctx.Done() == nil {
+		return client
+	}
+
+	ret := make(chan Response, 1)
+	go func() {
+		select {
+		case r := <-client:
+			ret <- r
+		case <-ctx.Done():
+			c.clients.lock.Lock()
+			pending := c.clients.clients[reqId]
+			delete(c.clients.clients, reqId)
+			if pending != nil && req["method"] == "create" {
+				// KMS creates the object anyway, which nobody would release
+				c.clients.clients[reqId] = &pendingRequest{
+					client: make(chan Response, 1),
+					done:   c.releaseCreated,
+				}
+			}
+			c.clients.lock.Unlock()
+			if pending == nil {
+				// the response is being delivered
+				ret <- <-client
+				break
+			}
+			errresp := Response{
+				Id: reqId,
+				Error: &Error{
+					Code:    RequestCanceled,
+					Message: ctx.Err().Error(),
+				},
+			}
+			done(errresp)
+			ret <- errresp
+		}
+		close(ret)
+	}()
+	return ret
+}
+
+// releaseCreated releases the object created by a request canceled before
+// its response.
+func (c *Connection) releaseCreated(r Response) {
+	id, _ := r.Result["value"].(string)
+	if r.Error != nil || id == "" {
+		return
+	}
+	obj := &MediaObject{}
+	HydrateMediaObject(id, nil, c.base(), obj)
+	// the response loop cannot wait for the response of the release
+	go obj.Release()
 }
 
 func (c *Connection) Subscribe(event, objectId, handlerId string, handler eventHandler) {
+	if c.connState == nil {
+		return
+	}
 	var oh map[string]map[string]eventHandler
 	var ok bool
 
+	c.events.lock.Lock()
+	defer c.events.lock.Unlock()
+
 	if oh, ok = c.events.subscribers[event]; !ok {
 		c.events.subscribers[event] = make(map[string]map[string]eventHandler)
 		oh = c.events.subscribers[event]
@@ -298,10 +626,16 @@ func (c *Connection) Subscribe(event, objectId, handlerId string, handler eventH
 }
 
 func (c *Connection) Unsubscribe(event, objectId, handlerId string) {
+	if c.connState == nil {
+		return
+	}
 	var oh map[string]map[string]eventHandler
 	var he map[string]eventHandler
 	var ok bool
 
+	c.events.lock.Lock()
+	defer c.events.lock.Unlock()
+
 	if oh, ok = c.events.subscribers[event]; !ok {
 		return // not found
 	}
@@ -0,0 +1,46 @@
+package kurento_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	kurento "github.com/safermobility/kurento-go/v6"
+)
+
+func TestZeroConnection(t *testing.T) {
+	conn := &kurento.Connection{}
+	conn.Use(kurento.LoggingInterceptor(nil))
+
+	pipeline := &kurento.MediaPipeline{}
+	err := conn.WithContext(context.Background()).Create(pipeline, nil)
+	if err == nil {
+		t.Fatal("Create succeeded")
+	}
+	r := <-conn.Request(map[string]interface{}{"method": "ping"})
+	if r.Error == nil || r.Error.Code != kurento.ConnectionLost {
+		t.Errorf("Request() = %+v, want ConnectionLost", r)
+	}
+	if err := conn.Close(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCanceledCreate(t *testing.T) {
+	s := &script{}
+	s.id++
+	s.add("sent", map[string]interface{}{"method": "create", "params": map[string]interface{}{"type": "MediaPipeline"}, "id": s.id})
+	// the object is created after the request is canceled
+	s.delay(200 * time.Millisecond)
+	s.add("received", map[string]interface{}{"jsonrpc": "2.0", "id": s.id, "result": map[string]interface{}{"value": "late"}})
+	s.release("late")
+	conn, srv := s.serve(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := conn.WithContext(ctx).Create(&kurento.MediaPipeline{}, nil)
+	if err == nil {
+		t.Fatal("Create succeeded")
+	}
+	done(t, srv)
+}